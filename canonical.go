@@ -0,0 +1,222 @@
+package gores
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode/utf16"
+)
+
+// MarshalCanonical encodes v as JSON in the RFC 8785 JSON Canonicalization Scheme (JCS),
+// so that equal values always produce identical bytes, e.g. for signing a ResponseVM.
+func MarshalCanonical(v interface{}) ([]byte, error) {
+	body, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+
+	return CanonicalJSON(body)
+}
+
+// CanonicalJSON rewrites a JSON document in the RFC 8785 JSON Canonicalization Scheme:
+// no insignificant whitespace, object members sorted by their UTF-16 code units, numbers
+// in their shortest ECMAScript form and strings with minimal escaping. Integer literals are
+// kept as written so that identifiers above 2^53 are not rounded. Documents with duplicate
+// object members or non-integer numbers outside the IEEE 754 double range are rejected.
+func CanonicalJSON(data []byte) ([]byte, error) {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+
+	var out bytes.Buffer
+	if err := writeCanonical(&out, decoder); err != nil {
+		return nil, err
+	}
+
+	// Trailing data after the document is not valid JSON
+	if _, err := decoder.Token(); err != io.EOF {
+		return nil, fmt.Errorf("gores: canonical JSON: unexpected data after the document")
+	}
+
+	return out.Bytes(), nil
+}
+
+// writeCanonical writes the next JSON value read from decoder in canonical form.
+func writeCanonical(out *bytes.Buffer, decoder *json.Decoder) error {
+	token, err := decoder.Token()
+	if err != nil {
+		return err
+	}
+
+	switch typed := token.(type) {
+	case json.Delim:
+		if typed == '[' {
+			return writeCanonicalArray(out, decoder)
+		}
+		return writeCanonicalObject(out, decoder)
+	case string:
+		writeCanonicalString(out, typed)
+	case json.Number:
+		number, err := canonicalNumber(typed)
+		if err != nil {
+			return err
+		}
+		out.WriteString(number)
+	case bool:
+		out.WriteString(strconv.FormatBool(typed))
+	case nil:
+		out.WriteString("null")
+	}
+
+	return nil
+}
+
+// writeCanonicalArray writes the remaining items of an array whose '[' was consumed.
+func writeCanonicalArray(out *bytes.Buffer, decoder *json.Decoder) error {
+	out.WriteByte('[')
+	for i := 0; decoder.More(); i++ {
+		if i > 0 {
+			out.WriteByte(',')
+		}
+
+		if err := writeCanonical(out, decoder); err != nil {
+			return err
+		}
+	}
+	out.WriteByte(']')
+
+	// Consume the closing ']'
+	_, err := decoder.Token()
+	return err
+}
+
+// writeCanonicalObject writes the remaining members of an object whose '{' was consumed.
+// Members are buffered so they can be sorted once all keys are known.
+func writeCanonicalObject(out *bytes.Buffer, decoder *json.Decoder) error {
+	type member struct {
+		key   string
+		units []uint16
+		value []byte
+	}
+
+	var members []member
+	seen := make(map[string]bool)
+	for decoder.More() {
+		token, err := decoder.Token()
+		if err != nil {
+			return err
+		}
+
+		key := token.(string)
+		if seen[key] {
+			return fmt.Errorf("gores: canonical JSON: duplicate object member %q", key)
+		}
+		seen[key] = true
+
+		var value bytes.Buffer
+		if err := writeCanonical(&value, decoder); err != nil {
+			return err
+		}
+
+		members = append(members, member{key: key, units: utf16.Encode([]rune(key)), value: value.Bytes()})
+	}
+
+	// Consume the closing '}'
+	if _, err := decoder.Token(); err != nil {
+		return err
+	}
+
+	sort.Slice(members, func(i, j int) bool {
+		return compareUTF16(members[i].units, members[j].units) < 0
+	})
+
+	out.WriteByte('{')
+	for i, m := range members {
+		if i > 0 {
+			out.WriteByte(',')
+		}
+
+		writeCanonicalString(out, m.key)
+		out.WriteByte(':')
+		out.Write(m.value)
+	}
+	out.WriteByte('}')
+
+	return nil
+}
+
+// compareUTF16 compares two strings by their UTF-16 code units, as RFC 8785 requires.
+func compareUTF16(a, b []uint16) int {
+	for i := 0; i < len(a) && i < len(b); i++ {
+		if a[i] != b[i] {
+			return int(a[i]) - int(b[i])
+		}
+	}
+
+	return len(a) - len(b)
+}
+
+// canonicalNumber formats a JSON number as ECMAScript's Number.prototype.toString does.
+// encoding/json formats float64 values exactly that way. Integer literals are returned
+// unchanged, since a float64 cannot hold integers above 2^53 without losing precision.
+func canonicalNumber(number json.Number) (string, error) {
+	if !strings.ContainsAny(string(number), ".eE") {
+		// Negative zero is written as 0
+		if number == "-0" {
+			return "0", nil
+		}
+
+		return string(number), nil
+	}
+
+	value, err := strconv.ParseFloat(string(number), 64)
+	if err != nil || math.IsInf(value, 0) {
+		return "", fmt.Errorf("gores: canonical JSON: number %s is not an IEEE 754 double", number)
+	}
+
+	// Negative zero is written as 0
+	if value == 0 {
+		return "0", nil
+	}
+
+	encoded, err := json.Marshal(value)
+	return string(encoded), err
+}
+
+// writeCanonicalString writes s as a JSON string, escaping only what RFC 8785 requires.
+func writeCanonicalString(out *bytes.Buffer, s string) {
+	const hex = "0123456789abcdef"
+
+	out.WriteByte('"')
+	for _, r := range s {
+		switch r {
+		case '"':
+			out.WriteString(`\"`)
+		case '\\':
+			out.WriteString(`\\`)
+		case '\b':
+			out.WriteString(`\b`)
+		case '\f':
+			out.WriteString(`\f`)
+		case '\n':
+			out.WriteString(`\n`)
+		case '\r':
+			out.WriteString(`\r`)
+		case '\t':
+			out.WriteString(`\t`)
+		default:
+			if r < 0x20 {
+				out.WriteString(`\u00`)
+				out.WriteByte(hex[r>>4])
+				out.WriteByte(hex[r&0xf])
+			} else {
+				out.WriteRune(r)
+			}
+		}
+	}
+	out.WriteByte('"')
+}
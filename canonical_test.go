@@ -0,0 +1,99 @@
+package gores
+
+import "testing"
+
+func TestCanonicalJSON(t *testing.T) {
+	testCases := []struct {
+		Name          string
+		Input         string
+		Expected      string
+		ExpectedError bool
+	}{
+		{
+			Name:     "RemovesWhitespace",
+			Input:    "{ \"a\" : [ 1 , true , null ] }\n",
+			Expected: `{"a":[1,true,null]}`,
+		},
+		{
+			Name:     "RFC8785Numbers",
+			Input:    `[333333333.33333329, 1E30, 4.50, 2e-3, 0.000000000000000000000000001, -0, 1e21, 1e-7, 100]`,
+			Expected: `[333333333.3333333,1e+30,4.5,0.002,1e-27,0,1e+21,1e-7,100]`,
+		},
+		{
+			Name:     "LargeIntegers",
+			Input:    `{"id":9007199254740993,"ids":[1234567890123456789,-9223372036854775808,-0]}`,
+			Expected: `{"id":9007199254740993,"ids":[1234567890123456789,-9223372036854775808,0]}`,
+		},
+		{
+			Name:     "RFC8785Sorting",
+			Input:    `{"\u20ac":"Euro Sign","\r":"Carriage Return","\ufb33":"Hebrew Letter Dalet With Dagesh","1":"One","\ud83d\ude00":"Emoji: Grinning Face","\u0080":"Control","\u00f6":"Latin Small Letter O With Diaeresis"}`,
+			Expected: "{\"\\r\":\"Carriage Return\",\"1\":\"One\",\"\u0080\":\"Control\",\"ö\":\"Latin Small Letter O With Diaeresis\",\"€\":\"Euro Sign\",\"😀\":\"Emoji: Grinning Face\",\"\ufb33\":\"Hebrew Letter Dalet With Dagesh\"}",
+		},
+		{
+			Name:     "StringEscaping",
+			Input:    `"\u0041\u00e9\u000f\n\"\\\/<>&\u2028"`,
+			Expected: "\"Aé\\u000f\\n\\\"\\\\/<>&\u2028\"",
+		},
+		{
+			Name:     "NestedObjects",
+			Input:    `{"b":{"z":1,"y":[{"d":2,"c":1}]},"a":"x"}`,
+			Expected: `{"a":"x","b":{"y":[{"c":1,"d":2}],"z":1}}`,
+		},
+		{
+			Name:          "DuplicateMember",
+			Input:         `{"a":1,"a":2}`,
+			ExpectedError: true,
+		},
+		{
+			Name:          "NumberOutOfRange",
+			Input:         `[1e400]`,
+			ExpectedError: true,
+		},
+		{
+			Name:          "TrailingData",
+			Input:         `{} {}`,
+			ExpectedError: true,
+		},
+		{
+			Name:          "Invalid",
+			Input:         `{"a":}`,
+			ExpectedError: true,
+		},
+	}
+
+	for i := range testCases {
+		t.Run(testCases[i].Name, func(t *testing.T) {
+			actual, err := CanonicalJSON([]byte(testCases[i].Input))
+			if testCases[i].ExpectedError {
+				if err == nil {
+					t.Errorf("expected an error, got %s", actual)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("unexpected error %v", err)
+			}
+
+			if string(actual) != testCases[i].Expected {
+				t.Errorf("expected canonical JSON is\n%s\ngot\n%s", testCases[i].Expected, actual)
+			}
+		})
+	}
+}
+
+func TestMarshalCanonical(t *testing.T) {
+	vm := NewResponseVM[*someStruct]().
+		SetCode(200).
+		SetData(&someStruct{SomeField: "<b>"})
+
+	actual, err := MarshalCanonical(vm)
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+
+	expected := `{"code":200,"data":{"SomeField":"<b>"}}`
+	if string(actual) != expected {
+		t.Errorf("expected canonical JSON is %s, got %s", expected, actual)
+	}
+}
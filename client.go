@@ -0,0 +1,382 @@
+package gores
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Client performs HTTP requests against APIs that respond with gores envelopes.
+// It wraps an *http.Client and optionally retries requests the server reports as retryable.
+type Client struct {
+	httpClient  *http.Client
+	retryPolicy *RetryPolicy
+	schema      *EnvelopeSchema
+	verifier    *SignatureVerifier
+	digest      bool
+}
+
+// RetryPolicy controls how a Client retries retryable responses.
+// Delays come from the Retry-After header or the error's retry_after field when present,
+// and otherwise from exponential backoff with jitter between BaseDelay and MaxDelay.
+type RetryPolicy struct {
+	MaxAttempts int           // Total attempts including the first one
+	BaseDelay   time.Duration // Backoff delay before the first retry
+	MaxDelay    time.Duration // Upper bound for any single delay
+}
+
+// NewRetryPolicy creates a new RetryPolicy with three attempts and 100ms to 5s backoff.
+func NewRetryPolicy() *RetryPolicy {
+	return &RetryPolicy{
+		MaxAttempts: 3,
+		BaseDelay:   100 * time.Millisecond,
+		MaxDelay:    5 * time.Second,
+	}
+}
+
+// SetMaxAttempts sets the total number of attempts including the first one.
+// This method uses method chaining pattern for fluent API design.
+func (p *RetryPolicy) SetMaxAttempts(maxAttempts int) *RetryPolicy {
+	p.MaxAttempts = maxAttempts
+	return p
+}
+
+// SetBackoff sets the base and maximum backoff delays. A zero base delay retries immediately.
+// It panics when a delay is negative or the base delay exceeds the maximum.
+func (p *RetryPolicy) SetBackoff(baseDelay, maxDelay time.Duration) *RetryPolicy {
+	if baseDelay < 0 || maxDelay < baseDelay {
+		panic(fmt.Sprintf("gores: backoff needs 0 <= baseDelay <= maxDelay, got %s and %s", baseDelay, maxDelay))
+	}
+
+	p.BaseDelay = baseDelay
+	p.MaxDelay = maxDelay
+	return p
+}
+
+// backoff returns the jittered exponential delay before the given retry (1-based).
+// Delays are clamped to MaxDelay, and invalid values assigned to the fields directly to zero.
+func (p *RetryPolicy) backoff(retry int) time.Duration {
+	delay := p.BaseDelay
+	for i := 1; i < retry && delay < p.MaxDelay; i++ {
+		// Stop doubling before it can overflow
+		if delay > p.MaxDelay/2 {
+			delay = p.MaxDelay
+			break
+		}
+		delay *= 2
+	}
+
+	if delay > p.MaxDelay {
+		delay = p.MaxDelay
+	}
+	if delay <= 0 {
+		return 0
+	}
+
+	// Full jitter spreads retries from many clients over the interval
+	return time.Duration(rand.Int63n(int64(delay)))
+}
+
+// NewClient creates a new Client using httpClient, or http.DefaultClient when nil.
+// Retries are disabled until a RetryPolicy is set.
+func NewClient(httpClient *http.Client) *Client {
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+
+	return &Client{
+		httpClient: httpClient,
+	}
+}
+
+// SetRetryPolicy enables retries of retryable responses using policy.
+// This method uses method chaining pattern for fluent API design.
+func (c *Client) SetRetryPolicy(policy *RetryPolicy) *Client {
+	c.retryPolicy = policy
+	return c
+}
+
+// SetSchema sets the envelope shape the server responds with.
+// It is used when inspecting responses for retryability; pass the same schema
+// to DecodeResponseWithSchema when decoding.
+func (c *Client) SetSchema(schema *EnvelopeSchema) *Client {
+	c.schema = schema
+	return c
+}
+
+// SetVerifier requires every response with a body to carry a valid signature.
+// Do then fails with an error wrapping ErrSignatureMissing or ErrSignatureInvalid otherwise.
+func (c *Client) SetVerifier(verifier *SignatureVerifier) *Client {
+	c.verifier = verifier
+	return c
+}
+
+// SetVerifyDigest requires every response with a body to match its RFC 9530 Content-Digest,
+// sent as a header or trailer. Do then fails with an *IntegrityError otherwise. Requests
+// without an Accept-Encoding header ask for gzip themselves, so the digest is checked
+// on the bytes received before the body is decompressed.
+func (c *Client) SetVerifyDigest(verify bool) *Client {
+	c.digest = verify
+	return c
+}
+
+// Do sends req and returns the final response.
+// A response is retried when its status is 429, 503 or 504 or its gores error is marked
+// retryable, as long as attempts remain and the request body can be replayed via GetBody.
+// Only idempotent methods and requests carrying an Idempotency-Key header are retried.
+// Retries the server asks to delay beyond MaxDelay are not attempted, and waiting
+// between attempts stops early when the request context is done.
+// When a verifier is set or digest verification is enabled, the final response is checked.
+func (c *Client) Do(req *http.Request) (*http.Response, error) {
+	// The transport would decompress gzip transparently and discard the bytes the digest covers
+	negotiateGzip := c.digest && req.Header.Get("Accept-Encoding") == ""
+	if negotiateGzip {
+		req = req.Clone(req.Context())
+		if req.Header == nil {
+			req.Header = http.Header{}
+		}
+		req.Header.Set("Accept-Encoding", "gzip")
+	}
+
+	resp, err := c.do(req)
+	if err != nil {
+		return resp, err
+	}
+
+	if c.digest {
+		if err := VerifyContentDigest(resp); err != nil {
+			resp.Body.Close()
+			return nil, err
+		}
+	}
+
+	if negotiateGzip {
+		if err := decompressGzip(resp); err != nil {
+			resp.Body.Close()
+			return nil, err
+		}
+	}
+
+	if c.verifier != nil {
+		if err := c.verifier.VerifyResponse(resp); err != nil {
+			resp.Body.Close()
+			return nil, err
+		}
+	}
+
+	return resp, nil
+}
+
+// do sends req, retrying according to the retry policy. Retries send a clone of req
+// with a fresh body, so the caller's request is never modified.
+func (c *Client) do(req *http.Request) (*http.Response, error) {
+	attemptReq := req
+	for attempt := 1; ; attempt++ {
+		resp, err := c.httpClient.Do(attemptReq)
+		if err != nil || !c.canRetry(req, attempt) {
+			return resp, err
+		}
+
+		retryable, delay, err := inspectRetry(resp, c.schema)
+		if err != nil || !retryable {
+			return resp, err
+		}
+
+		if delay <= 0 {
+			delay = c.retryPolicy.backoff(attempt)
+		}
+
+		// Never retry sooner than the server asked; give up instead of waiting too long
+		if delay > c.retryPolicy.MaxDelay {
+			return resp, nil
+		}
+
+		resp.Body.Close()
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-req.Context().Done():
+			timer.Stop()
+			return nil, req.Context().Err()
+		case <-timer.C:
+		}
+
+		attemptReq = req.Clone(req.Context())
+		if req.Body != nil && req.Body != http.NoBody {
+			body, err := req.GetBody()
+			if err != nil {
+				return nil, err
+			}
+			attemptReq.Body = body
+		}
+	}
+}
+
+// canRetry reports whether another attempt may follow the given one.
+func (c *Client) canRetry(req *http.Request, attempt int) bool {
+	if c.retryPolicy == nil || attempt >= c.retryPolicy.MaxAttempts {
+		return false
+	}
+
+	// Repeating other requests could apply their side effects twice
+	if !isIdempotentMethod(req.Method) && req.Header.Get("Idempotency-Key") == "" {
+		return false
+	}
+
+	// Bodies that cannot be rewound cannot be sent again
+	return req.Body == nil || req.Body == http.NoBody || req.GetBody != nil
+}
+
+// isIdempotentMethod reports whether repeating a request with the method has the same
+// effect as sending it once, per RFC 9110.
+func isIdempotentMethod(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodTrace, http.MethodPut, http.MethodDelete:
+		return true
+	}
+
+	return false
+}
+
+// inspectRetry determines whether resp is retryable and the delay the server asked for.
+// The body of error responses is buffered and restored so the caller can still decode it;
+// successful responses are never retried and are left untouched.
+func inspectRetry(resp *http.Response, schema *EnvelopeSchema) (bool, time.Duration, error) {
+	if resp.StatusCode < http.StatusBadRequest {
+		return false, 0, nil
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return false, 0, err
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+
+	// Bodies compressed for digest verification are only decompressed after it
+	if strings.EqualFold(resp.Header.Get("Content-Encoding"), "gzip") {
+		if reader, err := gzip.NewReader(bytes.NewReader(body)); err == nil {
+			body, _ = io.ReadAll(reader)
+		}
+	}
+
+	var envelope struct {
+		Error *ResponseErrorVM `json:"error"`
+	}
+	if normalized, err := schema.Decode(body); err == nil {
+		json.Unmarshal(normalized, &envelope)
+	}
+
+	retryable := isRetryableCode(resp.StatusCode)
+	delay := parseRetryAfter(resp.Header.Get("Retry-After"))
+
+	if envelope.Error != nil {
+		retryable = retryable || envelope.Error.Retryable
+
+		if delay <= 0 && envelope.Error.RetryAfter > 0 {
+			delay = time.Duration(envelope.Error.RetryAfter) * time.Second
+		}
+	}
+
+	return retryable, delay, nil
+}
+
+// parseRetryAfter parses a Retry-After header given in seconds or as an HTTP date.
+func parseRetryAfter(value string) time.Duration {
+	if value == "" {
+		return 0
+	}
+
+	if seconds, err := strconv.Atoi(value); err == nil {
+		return time.Duration(seconds) * time.Second
+	}
+
+	if at, err := http.ParseTime(value); err == nil {
+		return time.Until(at)
+	}
+
+	return 0
+}
+
+// DecodeResponse reads and closes the body of resp and decodes it as a gores envelope.
+// When the body omits the status code, the HTTP status code of resp is used instead.
+func DecodeResponse[T comparable](resp *http.Response) (*ResponseVM[T], error) {
+	return DecodeResponseWithSchema[T](resp, nil)
+}
+
+// DecodeResponseWithSchema is like DecodeResponse for servers rendering with a custom
+// EnvelopeSchema. A nil schema decodes the default envelope shape.
+func DecodeResponseWithSchema[T comparable](resp *http.Response, schema *EnvelopeSchema) (*ResponseVM[T], error) {
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	vm := NewResponseVM[T]()
+	if len(bytes.TrimSpace(body)) > 0 {
+		if body, err = schema.Decode(body); err != nil {
+			return nil, err
+		}
+
+		if err := json.Unmarshal(body, vm); err != nil {
+			return nil, err
+		}
+	}
+
+	if vm.Code == 0 {
+		vm.Code = resp.StatusCode
+	}
+
+	return vm, nil
+}
+
+// DecodeBareResponse reads and closes the body of resp rendered in bare mode.
+// Successful status codes decode the body into Data and failed ones into Error,
+// unwrapping it from errorKey when the server uses SetBareErrorKey. The returned
+// envelope carries the HTTP status code so both shapes are handled uniformly.
+func DecodeBareResponse[T comparable](resp *http.Response, errorKey string) (*ResponseVM[T], error) {
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	vm := NewResponseVM[T]().SetCode(resp.StatusCode)
+	if len(bytes.TrimSpace(body)) == 0 {
+		return vm, nil
+	}
+
+	if resp.StatusCode < http.StatusBadRequest {
+		if err := json.Unmarshal(body, &vm.Data); err != nil {
+			return nil, err
+		}
+
+		return vm, nil
+	}
+
+	if errorKey == "" {
+		errVM := NewResponseErrorVM()
+		if err := json.Unmarshal(body, errVM); err != nil {
+			return nil, err
+		}
+
+		return vm.SetError(errVM), nil
+	}
+
+	var wrapper map[string]*ResponseErrorVM
+	if err := json.Unmarshal(body, &wrapper); err != nil {
+		return nil, err
+	}
+
+	return vm.SetError(wrapper[errorKey]), nil
+}
@@ -0,0 +1,238 @@
+package gores
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/fikri240794/gocerr"
+)
+
+// newFlakyServer returns a server that fails with failure until it has been called failures times
+func newFlakyServer(failures int, failure func(w http.ResponseWriter, r *http.Request)) (*httptest.Server, *int) {
+	calls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		if calls <= failures {
+			failure(w, r)
+			return
+		}
+
+		NewRenderer().Render(w, r, NewResponseVM[*someStruct]().SetCode(http.StatusOK).SetData(&someStruct{SomeField: "ok"}))
+	}))
+
+	return server, &calls
+}
+
+func TestClient_Do(t *testing.T) {
+	unavailable := func(w http.ResponseWriter, r *http.Request) {
+		NewRenderer().RenderError(w, r, gocerr.New(http.StatusServiceUnavailable, "maintenance"))
+	}
+	retryableConflict := func(w http.ResponseWriter, r *http.Request) {
+		NewRenderer().Render(w, r, NewResponseVM[*someStruct]().
+			SetCode(http.StatusConflict).
+			SetError(NewResponseErrorVM().SetMessage("locked").SetRetryable(true)))
+	}
+	notRetryable := func(w http.ResponseWriter, r *http.Request) {
+		NewRenderer().RenderError(w, r, gocerr.New(http.StatusBadRequest, "bad request"))
+	}
+	longRetryAfter := func(w http.ResponseWriter, r *http.Request) {
+		NewRenderer().Render(w, r, NewResponseVM[*someStruct]().
+			SetCode(http.StatusServiceUnavailable).
+			SetError(NewResponseErrorVM().SetMessage("maintenance").SetRetryAfter(time.Hour)))
+	}
+
+	testCases := []struct {
+		Name          string
+		Failures      int
+		Failure       func(w http.ResponseWriter, r *http.Request)
+		Policy        *RetryPolicy
+		Method        string
+		Body          string
+		Key           string
+		ExpectedCalls int
+		ExpectedCode  int
+	}{
+		{Name: "NoPolicy", Failures: 1, Failure: unavailable, ExpectedCalls: 1, ExpectedCode: http.StatusServiceUnavailable},
+		{Name: "RetriesStatusCode", Failures: 2, Failure: unavailable, Policy: NewRetryPolicy(), ExpectedCalls: 3, ExpectedCode: http.StatusOK},
+		{Name: "RetriesFlag", Failures: 1, Failure: retryableConflict, Policy: NewRetryPolicy(), ExpectedCalls: 2, ExpectedCode: http.StatusOK},
+		{Name: "RetriesWithBody", Failures: 1, Failure: unavailable, Policy: NewRetryPolicy(), Method: http.MethodPut, Body: `{"a":1}`, ExpectedCalls: 2, ExpectedCode: http.StatusOK},
+		{Name: "NonIdempotentNotRetried", Failures: 1, Failure: unavailable, Policy: NewRetryPolicy(), Method: http.MethodPost, Body: `{"a":1}`, ExpectedCalls: 1, ExpectedCode: http.StatusServiceUnavailable},
+		{Name: "RetriesWithIdempotencyKey", Failures: 1, Failure: unavailable, Policy: NewRetryPolicy(), Method: http.MethodPost, Body: `{"a":1}`, Key: "k1", ExpectedCalls: 2, ExpectedCode: http.StatusOK},
+		{Name: "AttemptsExhausted", Failures: 5, Failure: unavailable, Policy: NewRetryPolicy().SetMaxAttempts(2), ExpectedCalls: 2, ExpectedCode: http.StatusServiceUnavailable},
+		{Name: "NotRetryable", Failures: 1, Failure: notRetryable, Policy: NewRetryPolicy(), ExpectedCalls: 1, ExpectedCode: http.StatusBadRequest},
+		{Name: "RetryAfterBeyondMaxDelay", Failures: 1, Failure: longRetryAfter, Policy: NewRetryPolicy(), ExpectedCalls: 1, ExpectedCode: http.StatusServiceUnavailable},
+	}
+
+	for i := range testCases {
+		t.Run(testCases[i].Name, func(t *testing.T) {
+			server, calls := newFlakyServer(testCases[i].Failures, testCases[i].Failure)
+			defer server.Close()
+
+			client := NewClient(server.Client())
+			if testCases[i].Policy != nil {
+				client.SetRetryPolicy(testCases[i].Policy.SetBackoff(time.Millisecond, 10*time.Millisecond))
+			}
+
+			method := testCases[i].Method
+			if method == "" {
+				method = http.MethodGet
+			}
+
+			req, _ := http.NewRequest(method, server.URL, strings.NewReader(testCases[i].Body))
+			if testCases[i].Key != "" {
+				req.Header.Set("Idempotency-Key", testCases[i].Key)
+			}
+
+			resp, err := client.Do(req)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			vm, err := DecodeResponse[*someStruct](resp)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if *calls != testCases[i].ExpectedCalls {
+				t.Errorf("expected calls is %d, got %d", testCases[i].ExpectedCalls, *calls)
+			}
+
+			if vm.Code != testCases[i].ExpectedCode {
+				t.Errorf("expected code is %d, got %d", testCases[i].ExpectedCode, vm.Code)
+			}
+		})
+	}
+}
+
+// TestClient_Do_ContextCanceled tests that waiting for a retry stops when the context is done
+func TestClient_Do_ContextCanceled(t *testing.T) {
+	server, _ := newFlakyServer(5, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Retry-After", "1")
+		w.WriteHeader(http.StatusServiceUnavailable)
+	})
+	defer server.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	req, _ := http.NewRequestWithContext(ctx, http.MethodGet, server.URL, nil)
+	client := NewClient(server.Client()).SetRetryPolicy(NewRetryPolicy())
+
+	if _, err := client.Do(req); err != context.DeadlineExceeded {
+		t.Errorf("expected error is %v, got %v", context.DeadlineExceeded, err)
+	}
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	if d := parseRetryAfter("7"); d != 7*time.Second {
+		t.Errorf("expected delay is %s, got %s", 7*time.Second, d)
+	}
+
+	if d := parseRetryAfter(time.Now().Add(time.Minute).UTC().Format(http.TimeFormat)); d <= 0 || d > time.Minute {
+		t.Errorf("expected delay within a minute, got %s", d)
+	}
+
+	if d := parseRetryAfter("soon"); d != 0 {
+		t.Errorf("expected delay is 0, got %s", d)
+	}
+}
+
+// TestInspectRetry_Success tests that successful responses are not buffered
+func TestInspectRetry_Success(t *testing.T) {
+	body := io.NopCloser(strings.NewReader(`{"code":200}`))
+	resp := &http.Response{StatusCode: http.StatusOK, Header: http.Header{}, Body: body}
+
+	retryable, _, err := inspectRetry(resp, nil)
+	if retryable || err != nil {
+		t.Errorf("expected not retryable, got %t and %v", retryable, err)
+	}
+
+	if resp.Body != body {
+		t.Error("expected body to be left untouched")
+	}
+}
+
+// TestClient_Do_KeepsRequest tests that retries do not modify the caller's request
+func TestClient_Do_KeepsRequest(t *testing.T) {
+	server, calls := newFlakyServer(1, func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	})
+	defer server.Close()
+
+	req, _ := http.NewRequest(http.MethodPut, server.URL, strings.NewReader(`{"a":1}`))
+	body := req.Body
+
+	client := NewClient(server.Client()).SetRetryPolicy(NewRetryPolicy().SetBackoff(time.Millisecond, 10*time.Millisecond))
+	resp, err := client.Do(req)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	resp.Body.Close()
+
+	if *calls != 2 {
+		t.Errorf("expected calls is %d, got %d", 2, *calls)
+	}
+
+	if req.Body != body {
+		t.Error("expected the request body not to be replaced")
+	}
+}
+
+func TestRetryPolicy_Backoff(t *testing.T) {
+	testCases := []struct {
+		Name      string
+		BaseDelay time.Duration
+		MaxDelay  time.Duration
+		Retry     int
+		Expected  time.Duration // Upper bound of the jittered delay
+	}{
+		{Name: "First", BaseDelay: 100 * time.Millisecond, MaxDelay: time.Second, Retry: 1, Expected: 100 * time.Millisecond},
+		{Name: "Doubled", BaseDelay: 100 * time.Millisecond, MaxDelay: time.Second, Retry: 3, Expected: 400 * time.Millisecond},
+		{Name: "Capped", BaseDelay: 100 * time.Millisecond, MaxDelay: time.Second, Retry: 10, Expected: time.Second},
+		{Name: "NoOverflow", BaseDelay: time.Second, MaxDelay: time.Duration(1<<63 - 1), Retry: 100, Expected: time.Duration(1<<63 - 1)},
+		{Name: "ZeroBaseDelay", MaxDelay: time.Second, Retry: 3},
+		{Name: "NegativeMaxDelay", BaseDelay: time.Second, MaxDelay: -time.Second, Retry: 1},
+		{Name: "NegativeBaseDelay", BaseDelay: -time.Second, MaxDelay: time.Second, Retry: 2},
+	}
+
+	for i := range testCases {
+		t.Run(testCases[i].Name, func(t *testing.T) {
+			policy := &RetryPolicy{BaseDelay: testCases[i].BaseDelay, MaxDelay: testCases[i].MaxDelay}
+
+			for j := 0; j < 20; j++ {
+				if delay := policy.backoff(testCases[i].Retry); delay < 0 || delay > testCases[i].Expected {
+					t.Fatalf("expected delay between 0 and %s, got %s", testCases[i].Expected, delay)
+				}
+			}
+		})
+	}
+}
+
+func TestRetryPolicy_SetBackoff_RejectsInvalid(t *testing.T) {
+	testCases := []struct {
+		Name      string
+		BaseDelay time.Duration
+		MaxDelay  time.Duration
+	}{
+		{Name: "NegativeBaseDelay", BaseDelay: -time.Second, MaxDelay: time.Second},
+		{Name: "NegativeMaxDelay", MaxDelay: -time.Second},
+		{Name: "BaseAboveMax", BaseDelay: 2 * time.Second, MaxDelay: time.Second},
+	}
+
+	for i := range testCases {
+		t.Run(testCases[i].Name, func(t *testing.T) {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("expected a panic for %s and %s", testCases[i].BaseDelay, testCases[i].MaxDelay)
+				}
+			}()
+
+			NewRetryPolicy().SetBackoff(testCases[i].BaseDelay, testCases[i].MaxDelay)
+		})
+	}
+}
@@ -0,0 +1,296 @@
+package gores
+
+import (
+	"bytes"
+	"compress/gzip"
+	"compress/zlib"
+	"io"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// EncoderFunc wraps w in a writer that compresses everything written to it with one
+// content coding. Closing the returned writer must flush the remaining data to w.
+type EncoderFunc func(w io.Writer) (io.WriteCloser, error)
+
+// DefaultCompressionThreshold is the body size in bytes below which compression is skipped,
+// since headers and framing outweigh the savings on small bodies.
+const DefaultCompressionThreshold = 1024
+
+// Compression negotiates a content coding from Accept-Encoding and compresses bodies.
+// gzip and deflate are built in; other codings such as br or zstd are plugged in with
+// SetEncoder. Codings registered later are preferred when the client accepts several equally.
+type Compression struct {
+	threshold int                    // Minimum body size to compress
+	codings   []string               // Supported codings, most preferred first
+	encoders  map[string]EncoderFunc // Encoder per coding
+}
+
+// NewCompression creates a new Compression supporting gzip and deflate, preferring gzip,
+// with a threshold of DefaultCompressionThreshold bytes.
+func NewCompression() *Compression {
+	return &Compression{
+		threshold: DefaultCompressionThreshold,
+		codings:   []string{"gzip", "deflate"},
+		encoders: map[string]EncoderFunc{
+			"gzip": func(w io.Writer) (io.WriteCloser, error) {
+				return gzip.NewWriter(w), nil
+			},
+			"deflate": func(w io.Writer) (io.WriteCloser, error) {
+				// The deflate coding is the zlib format of RFC 1950, not raw DEFLATE
+				return zlib.NewWriter(w), nil
+			},
+		},
+	}
+}
+
+// SetThreshold sets the body size in bytes below which responses are sent uncompressed.
+// This method uses method chaining pattern for fluent API design.
+func (c *Compression) SetThreshold(threshold int) *Compression {
+	c.threshold = threshold
+	return c
+}
+
+// SetEncoder registers the encoder of a content coding, making it the most preferred one,
+// e.g. SetEncoder("br", ...) with a brotli writer. A nil encoder removes the coding.
+func (c *Compression) SetEncoder(coding string, encoder EncoderFunc) *Compression {
+	coding = strings.ToLower(coding)
+
+	codings := make([]string, 0, len(c.codings)+1)
+	if encoder != nil {
+		codings = append(codings, coding)
+	}
+	for _, existing := range c.codings {
+		if existing != coding {
+			codings = append(codings, existing)
+		}
+	}
+
+	c.codings = codings
+	if encoder != nil {
+		c.encoders[coding] = encoder
+	} else {
+		delete(c.encoders, coding)
+	}
+
+	return c
+}
+
+// negotiate returns the supported coding the request accepts with the highest quality,
+// ties broken by server preference, or "" when the body should be sent as is. When no
+// coding is acceptable, not even identity, the body is sent as is instead of a 406.
+func (c *Compression) negotiate(r *http.Request) string {
+	if c == nil || r == nil {
+		return ""
+	}
+
+	qualities := make(map[string]float64)
+	wildcard := 0.0
+	for _, member := range strings.Split(strings.Join(r.Header.Values("Accept-Encoding"), ","), ",") {
+		coding, params, _ := strings.Cut(member, ";")
+		coding = strings.ToLower(trimHTTPSpace(coding))
+		if coding == "" {
+			continue
+		}
+
+		quality := 1.0
+		for _, param := range strings.Split(params, ";") {
+			name, value, _ := strings.Cut(param, "=")
+			if strings.EqualFold(trimHTTPSpace(name), "q") {
+				if parsed, err := strconv.ParseFloat(trimHTTPSpace(value), 64); err == nil {
+					quality = parsed
+				} else {
+					quality = 0
+				}
+			}
+		}
+
+		switch coding {
+		case "*":
+			wildcard = quality
+		case "x-gzip":
+			qualities["gzip"] = quality
+		default:
+			qualities[coding] = quality
+		}
+	}
+
+	best, bestQuality := "", 0.0
+	for _, coding := range c.codings {
+		quality, listed := qualities[coding]
+		if !listed {
+			quality = wildcard
+		}
+
+		if quality > bestQuality {
+			best, bestQuality = coding, quality
+		}
+	}
+
+	// An explicitly preferred identity coding wins over compression
+	if identity, listed := qualities["identity"]; listed && identity > bestQuality {
+		return ""
+	}
+
+	return best
+}
+
+// compress encodes body with coding.
+func (c *Compression) compress(coding string, body []byte) ([]byte, error) {
+	var out bytes.Buffer
+	encoder, err := c.encoders[coding](&out)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := encoder.Write(body); err != nil {
+		return nil, err
+	}
+
+	if err := encoder.Close(); err != nil {
+		return nil, err
+	}
+
+	return out.Bytes(), nil
+}
+
+// addVary adds a field name to the Vary header unless it is already listed.
+func addVary(header http.Header, field string) {
+	for _, value := range header.Values("Vary") {
+		for _, existing := range strings.Split(value, ",") {
+			existing = trimHTTPSpace(existing)
+			if existing == "*" || strings.EqualFold(existing, field) {
+				return
+			}
+		}
+	}
+
+	header.Add("Vary", field)
+}
+
+// encodedETag derives the strong entity tag of a compressed representation from the one
+// of the uncompressed body, since strong tags must differ between codings.
+// Weak entity tags are shared by all codings.
+func encodedETag(etag, coding string) string {
+	if coding == "" || !strings.HasPrefix(etag, `"`) {
+		return etag
+	}
+
+	return strings.TrimSuffix(etag, `"`) + "-" + coding + `"`
+}
+
+// isCompressedType reports whether a media type is already compressed, so that
+// compressing it again would only cost time.
+func isCompressedType(contentType string) bool {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return false
+	}
+
+	switch {
+	case mediaType == "image/svg+xml":
+		return false
+	case strings.HasPrefix(mediaType, "image/"), strings.HasPrefix(mediaType, "video/"), strings.HasPrefix(mediaType, "audio/"):
+		return true
+	}
+
+	switch mediaType {
+	case "application/zip", "application/gzip", "application/x-gzip", "application/zstd",
+		"application/x-bzip2", "application/x-7z-compressed", "application/x-rar-compressed":
+		return true
+	}
+
+	return false
+}
+
+// CompressWriter compresses a streamed response body with the coding negotiated for
+// the request. The decision is made when the status code is written: responses that
+// already carry a Content-Encoding, have an already compressed Content-Type or carry
+// no body are sent as is. The size threshold does not apply since the size is unknown,
+// and an ETag set by the handler is left unchanged. Call Close when done.
+type CompressWriter struct {
+	http.ResponseWriter
+	compression *Compression
+	coding      string
+	encoder     io.WriteCloser
+	wroteHeader bool
+	err         error
+}
+
+// NewWriter wraps w in a CompressWriter for the request r and sets Vary: Accept-Encoding.
+// To send an RFC 9530 digest of the compressed stream, wrap a DigestWriter.
+func (c *Compression) NewWriter(w http.ResponseWriter, r *http.Request) *CompressWriter {
+	addVary(w.Header(), "Accept-Encoding")
+
+	cw := &CompressWriter{ResponseWriter: w, compression: c}
+	if r != nil && r.Method != http.MethodHead {
+		cw.coding = c.negotiate(r)
+	}
+
+	return cw
+}
+
+// WriteHeader decides whether to compress and writes the status code.
+func (cw *CompressWriter) WriteHeader(code int) {
+	if cw.wroteHeader || code < http.StatusOK {
+		cw.ResponseWriter.WriteHeader(code)
+		return
+	}
+	cw.wroteHeader = true
+
+	header := cw.Header()
+	if cw.coding != "" && code != http.StatusNoContent && code != http.StatusNotModified &&
+		header.Get("Content-Encoding") == "" && !isCompressedType(header.Get("Content-Type")) {
+		cw.encoder, cw.err = cw.compression.encoders[cw.coding](cw.ResponseWriter)
+		if cw.err == nil {
+			header.Set("Content-Encoding", cw.coding)
+			header.Del("Content-Length")
+		}
+	}
+
+	cw.ResponseWriter.WriteHeader(code)
+}
+
+// Write compresses p into the response when compression was chosen.
+func (cw *CompressWriter) Write(p []byte) (int, error) {
+	if !cw.wroteHeader {
+		cw.WriteHeader(http.StatusOK)
+	}
+
+	if cw.err != nil {
+		return 0, cw.err
+	}
+
+	if cw.encoder == nil {
+		return cw.ResponseWriter.Write(p)
+	}
+
+	return cw.encoder.Write(p)
+}
+
+// Flush flushes the compressed data written so far to the client.
+func (cw *CompressWriter) Flush() {
+	if flusher, ok := cw.encoder.(interface{ Flush() error }); ok {
+		flusher.Flush()
+	}
+
+	if flusher, ok := cw.ResponseWriter.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
+
+// Close completes the compressed stream. Nothing may be written after Close.
+func (cw *CompressWriter) Close() error {
+	if cw.encoder == nil {
+		return cw.err
+	}
+
+	return cw.encoder.Close()
+}
+
+// Unwrap returns the underlying writer for http.ResponseController.
+func (cw *CompressWriter) Unwrap() http.ResponseWriter {
+	return cw.ResponseWriter
+}
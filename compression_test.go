@@ -0,0 +1,263 @@
+package gores
+
+import (
+	"bytes"
+	"compress/gzip"
+	"compress/zlib"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+// nopEncoder is a pluggable encoder that writes its input unchanged.
+func nopEncoder(w io.Writer) (io.WriteCloser, error) {
+	return nopWriteCloser{w}, nil
+}
+
+type nopWriteCloser struct{ io.Writer }
+
+func (nopWriteCloser) Close() error { return nil }
+
+func TestCompression_negotiate(t *testing.T) {
+	testCases := []struct {
+		Name           string
+		AcceptEncoding string
+		Compression    *Compression
+		Expected       string
+	}{
+		{Name: "None", AcceptEncoding: "", Compression: NewCompression(), Expected: ""},
+		{Name: "Gzip", AcceptEncoding: "gzip", Compression: NewCompression(), Expected: "gzip"},
+		{Name: "ServerPreference", AcceptEncoding: "deflate, gzip", Compression: NewCompression(), Expected: "gzip"},
+		{Name: "Quality", AcceptEncoding: "gzip;q=0.5, deflate", Compression: NewCompression(), Expected: "deflate"},
+		{Name: "Excluded", AcceptEncoding: "gzip;q=0, deflate;q=0", Compression: NewCompression(), Expected: ""},
+		{Name: "Wildcard", AcceptEncoding: "*", Compression: NewCompression(), Expected: "gzip"},
+		{Name: "WildcardWithExclusion", AcceptEncoding: "gzip;q=0, *;q=0.1", Compression: NewCompression(), Expected: "deflate"},
+		{Name: "Unsupported", AcceptEncoding: "br, zstd", Compression: NewCompression(), Expected: ""},
+		{Name: "Alias", AcceptEncoding: "x-gzip", Compression: NewCompression(), Expected: "gzip"},
+		{Name: "IdentityPreferred", AcceptEncoding: "gzip;q=0.2, identity", Compression: NewCompression(), Expected: ""},
+		{Name: "NothingAcceptable", AcceptEncoding: "identity;q=0, *;q=0", Compression: NewCompression(), Expected: ""},
+		{Name: "CaseAndSpaces", AcceptEncoding: " GZIP ; Q=0.8 ", Compression: NewCompression(), Expected: "gzip"},
+		{Name: "PluggedEncoderPreferred", AcceptEncoding: "gzip, deflate, br", Compression: NewCompression().SetEncoder("br", nopEncoder), Expected: "br"},
+		{Name: "RemovedEncoder", AcceptEncoding: "gzip, deflate", Compression: NewCompression().SetEncoder("gzip", nil), Expected: "deflate"},
+	}
+
+	for i := range testCases {
+		t.Run(testCases[i].Name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodGet, "/", nil)
+			if testCases[i].AcceptEncoding != "" {
+				r.Header.Set("Accept-Encoding", testCases[i].AcceptEncoding)
+			}
+
+			if actual := testCases[i].Compression.negotiate(r); actual != testCases[i].Expected {
+				t.Errorf("expected coding is %q, got %q", testCases[i].Expected, actual)
+			}
+		})
+	}
+}
+
+func TestRenderer_SetCompression(t *testing.T) {
+	large := NewResponseVM[*someStruct]().SetCode(http.StatusOK).SetData(&someStruct{SomeField: strings.Repeat("gores ", 300)})
+	small := NewResponseVM[*someStruct]().SetCode(http.StatusOK).SetData(&someStruct{SomeField: "small"})
+	if uncompressed, _ := NewRenderer().encode(large, nil); len(uncompressed) < DefaultCompressionThreshold {
+		t.Fatalf("expected the large envelope to exceed the threshold, got %d bytes", len(uncompressed))
+	}
+
+	decode := map[string]func(io.Reader) (io.Reader, error){
+		"":        func(r io.Reader) (io.Reader, error) { return r, nil },
+		"gzip":    func(r io.Reader) (io.Reader, error) { return gzip.NewReader(r) },
+		"deflate": func(r io.Reader) (io.Reader, error) { return zlib.NewReader(r) },
+	}
+
+	testCases := []struct {
+		Name           string
+		AcceptEncoding string
+		VM             *ResponseVM[*someStruct]
+		ExpectedCoding string
+	}{
+		{Name: "Gzip", AcceptEncoding: "gzip", VM: large, ExpectedCoding: "gzip"},
+		{Name: "Deflate", AcceptEncoding: "deflate", VM: large, ExpectedCoding: "deflate"},
+		{Name: "NotAccepted", AcceptEncoding: "", VM: large, ExpectedCoding: ""},
+		{Name: "BelowThreshold", AcceptEncoding: "gzip", VM: small, ExpectedCoding: ""},
+		{Name: "NothingAcceptableSendsIdentity", AcceptEncoding: "identity;q=0, *;q=0", VM: large, ExpectedCoding: ""},
+	}
+
+	for i := range testCases {
+		t.Run(testCases[i].Name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodGet, "/", nil)
+			r.Header.Set("Accept-Encoding", testCases[i].AcceptEncoding)
+			recorder := httptest.NewRecorder()
+
+			NewRenderer().SetCompression(NewCompression()).Render(recorder, r, testCases[i].VM)
+
+			if actual := recorder.Header().Get("Content-Encoding"); actual != testCases[i].ExpectedCoding {
+				t.Errorf("expected Content-Encoding is %q, got %q", testCases[i].ExpectedCoding, actual)
+			}
+
+			if actual := recorder.Header().Get("Vary"); actual != "Accept-Encoding" {
+				t.Errorf("expected Vary is Accept-Encoding, got %q", actual)
+			}
+
+			if recorder.Header().Get("Content-Length") != strconv.Itoa(recorder.Body.Len()) {
+				t.Errorf("expected Content-Length %d, got %s", recorder.Body.Len(), recorder.Header().Get("Content-Length"))
+			}
+
+			reader, err := decode[testCases[i].ExpectedCoding](recorder.Body)
+			if err != nil {
+				t.Fatalf("unexpected error %v", err)
+			}
+			body, _ := io.ReadAll(reader)
+
+			expected, _ := NewRenderer().encode(testCases[i].VM, nil)
+			if !bytes.Equal(body, expected) {
+				t.Errorf("expected decoded body is %s, got %s", expected, body)
+			}
+		})
+	}
+}
+
+func TestRenderer_SetCompression_ETag(t *testing.T) {
+	renderer := NewRenderer().SetETagMode(ETagStrong).SetCompression(NewCompression().SetThreshold(0))
+	vm := NewResponseVM[*someStruct]().SetCode(http.StatusOK).SetData(&someStruct{SomeField: "value"})
+	identityETag := computeETag([]byte(`{"code":200,"data":{"SomeField":"value"}}`), false)
+	gzipETag := encodedETag(identityETag, "gzip")
+
+	testCases := []struct {
+		Name           string
+		AcceptEncoding string
+		IfNoneMatch    string
+		VM             *ResponseVM[*someStruct]
+		ExpectedCode   int
+		ExpectedETag   string
+	}{
+		{Name: "Identity", VM: vm, ExpectedCode: http.StatusOK, ExpectedETag: identityETag},
+		{Name: "CodingSuffix", AcceptEncoding: "gzip", VM: vm, ExpectedCode: http.StatusOK, ExpectedETag: gzipETag},
+		{Name: "NotModifiedWithEncodedTag", AcceptEncoding: "gzip", IfNoneMatch: gzipETag, VM: vm, ExpectedCode: http.StatusNotModified, ExpectedETag: gzipETag},
+		{Name: "NotModifiedWithIdentityTag", AcceptEncoding: "gzip", IfNoneMatch: identityETag, VM: vm, ExpectedCode: http.StatusNotModified, ExpectedETag: gzipETag},
+		{Name: "VersionSuffixed", AcceptEncoding: "gzip", VM: NewResponseVM[*someStruct]().SetCode(http.StatusOK).SetVersion("v7"), ExpectedCode: http.StatusOK, ExpectedETag: `"v7-gzip"`},
+		{Name: "VersionIdentity", VM: NewResponseVM[*someStruct]().SetCode(http.StatusOK).SetVersion("v7"), ExpectedCode: http.StatusOK, ExpectedETag: `"v7"`},
+		{Name: "VersionNotModified", AcceptEncoding: "gzip", IfNoneMatch: `"v7-gzip"`, VM: NewResponseVM[*someStruct]().SetCode(http.StatusOK).SetVersion("v7"), ExpectedCode: http.StatusNotModified, ExpectedETag: `"v7-gzip"`},
+	}
+
+	for i := range testCases {
+		t.Run(testCases[i].Name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodGet, "/", nil)
+			r.Header.Set("Accept-Encoding", testCases[i].AcceptEncoding)
+			r.Header.Set("If-None-Match", testCases[i].IfNoneMatch)
+			recorder := httptest.NewRecorder()
+
+			renderer.Render(recorder, r, testCases[i].VM)
+
+			if recorder.Code != testCases[i].ExpectedCode {
+				t.Errorf("expected status code is %d, got %d", testCases[i].ExpectedCode, recorder.Code)
+			}
+
+			if actual := recorder.Header().Get("ETag"); actual != testCases[i].ExpectedETag {
+				t.Errorf("expected ETag is %s, got %s", testCases[i].ExpectedETag, actual)
+			}
+
+			if actual := recorder.Header().Get("Vary"); actual != "Accept-Encoding" {
+				t.Errorf("expected Vary is Accept-Encoding, got %q", actual)
+			}
+		})
+	}
+}
+
+func TestCompression_NewWriter(t *testing.T) {
+	testCases := []struct {
+		Name           string
+		AcceptEncoding string
+		ContentType    string
+		Method         string
+		ExpectedCoding string
+	}{
+		{Name: "Gzip", AcceptEncoding: "gzip", ContentType: "application/x-ndjson", ExpectedCoding: "gzip"},
+		{Name: "NotAccepted", ContentType: "application/x-ndjson", ExpectedCoding: ""},
+		{Name: "AlreadyCompressed", AcceptEncoding: "gzip", ContentType: "image/png", ExpectedCoding: ""},
+		{Name: "Head", AcceptEncoding: "gzip", ContentType: "application/x-ndjson", Method: http.MethodHead, ExpectedCoding: ""},
+	}
+
+	for i := range testCases {
+		t.Run(testCases[i].Name, func(t *testing.T) {
+			method := testCases[i].Method
+			if method == "" {
+				method = http.MethodGet
+			}
+
+			r := httptest.NewRequest(method, "/", nil)
+			r.Header.Set("Accept-Encoding", testCases[i].AcceptEncoding)
+			recorder := httptest.NewRecorder()
+			recorder.Header().Set("Content-Type", testCases[i].ContentType)
+			recorder.Header().Set("Content-Length", "12")
+
+			cw := NewCompression().NewWriter(recorder, r)
+			cw.Write([]byte("{\"line\":1}\n"))
+			cw.Flush()
+			cw.Close()
+
+			if actual := recorder.Header().Get("Content-Encoding"); actual != testCases[i].ExpectedCoding {
+				t.Fatalf("expected Content-Encoding is %q, got %q", testCases[i].ExpectedCoding, actual)
+			}
+
+			if actual := recorder.Header().Get("Vary"); actual != "Accept-Encoding" {
+				t.Errorf("expected Vary is Accept-Encoding, got %q", actual)
+			}
+
+			body := recorder.Body.String()
+			if testCases[i].ExpectedCoding != "" {
+				if recorder.Header().Get("Content-Length") != "" {
+					t.Errorf("expected Content-Length to be removed")
+				}
+
+				reader, err := gzip.NewReader(recorder.Body)
+				if err != nil {
+					t.Fatalf("unexpected error %v", err)
+				}
+				decoded, _ := io.ReadAll(reader)
+				body = string(decoded)
+			}
+
+			if body != "{\"line\":1}\n" {
+				t.Errorf("expected body is the written line, got %q", body)
+			}
+		})
+	}
+}
+
+func TestCompression_NewWriter_Digest(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/x-ndjson")
+
+		// The digest covers the compressed content as sent
+		dw := NewDigestWriter(w, DigestSHA256)
+		cw := NewCompression().NewWriter(dw, r)
+		for i := 0; i < 3; i++ {
+			cw.Write([]byte(strings.Repeat("x", 100) + "\n"))
+			cw.Flush()
+		}
+		cw.Close()
+		dw.Close()
+	}))
+	defer server.Close()
+
+	req, _ := http.NewRequest(http.MethodGet, server.URL, nil)
+	req.Header.Set("Accept-Encoding", "gzip")
+
+	resp, err := NewClient(server.Client()).SetVerifyDigest(true).Do(req)
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	defer resp.Body.Close()
+
+	reader, err := gzip.NewReader(resp.Body)
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+
+	body, _ := io.ReadAll(reader)
+	if len(body) != 303 {
+		t.Errorf("expected 303 decompressed bytes, got %d", len(body))
+	}
+}
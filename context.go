@@ -0,0 +1,63 @@
+package gores
+
+import (
+	"context"
+	"errors"
+	"net/http"
+)
+
+// StatusClientClosedRequest is the non-standard status code used when the client
+// canceled the request before a response was produced.
+const StatusClientClosedRequest = 499
+
+// contextError combines a context's error with its cause.
+type contextError struct {
+	err   error
+	cause error
+}
+
+// ContextError returns the error of a done context combined with its context.Cause.
+// The result matches both context.Canceled / context.DeadlineExceeded and the cause
+// via errors.Is and errors.As, so SetErrorFromError maps it to the right status code
+// while keeping the cause's message. It returns nil if ctx is not done.
+func ContextError(ctx context.Context) error {
+	err := ctx.Err()
+	if err == nil {
+		return nil
+	}
+
+	cause := context.Cause(ctx)
+	if cause == nil || cause == err {
+		return err
+	}
+
+	return &contextError{err: err, cause: cause}
+}
+
+// Error returns the message of the cause.
+func (e *contextError) Error() string {
+	return e.cause.Error()
+}
+
+// Unwrap returns both the cause and the context error.
+func (e *contextError) Unwrap() []error {
+	return []error{e.cause, e.err}
+}
+
+// contextErrorCode maps context cancellation errors to HTTP status codes.
+// It returns 0 for errors unrelated to context cancellation.
+func contextErrorCode(err error) int {
+	switch {
+	case errors.Is(err, context.DeadlineExceeded):
+		return http.StatusGatewayTimeout
+	case errors.Is(err, context.Canceled):
+		return StatusClientClosedRequest
+	}
+
+	return 0
+}
+
+// isClientGone reports whether the client disconnected before the response was written.
+func isClientGone(r *http.Request) bool {
+	return r != nil && errors.Is(r.Context().Err(), context.Canceled)
+}
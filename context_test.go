@@ -0,0 +1,86 @@
+package gores
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/fikri240794/gocerr"
+)
+
+func TestResponseVM_SetErrorFromError_Context(t *testing.T) {
+	errDatabaseSlow := errors.New("database too slow")
+
+	timedOut, cancelTimedOut := context.WithTimeoutCause(context.Background(), 0, errDatabaseSlow)
+	defer cancelTimedOut()
+
+	testCases := []struct {
+		Name            string
+		Err             error
+		ExpectedCode    int
+		ExpectedMessage string
+	}{
+		{Name: "Canceled", Err: context.Canceled, ExpectedCode: StatusClientClosedRequest, ExpectedMessage: "context canceled"},
+		{Name: "DeadlineExceeded", Err: context.DeadlineExceeded, ExpectedCode: http.StatusGatewayTimeout, ExpectedMessage: "context deadline exceeded"},
+		{Name: "WrappedDeadlineExceeded", Err: fmt.Errorf("query users: %w", context.DeadlineExceeded), ExpectedCode: http.StatusGatewayTimeout, ExpectedMessage: "query users: context deadline exceeded"},
+		{Name: "ContextCause", Err: ContextError(timedOut), ExpectedCode: http.StatusGatewayTimeout, ExpectedMessage: "database too slow"},
+		{Name: "CustomErrorWins", Err: fmt.Errorf("%w: %w", gocerr.New(http.StatusConflict, "locked"), context.Canceled), ExpectedCode: http.StatusConflict},
+	}
+
+	for i := range testCases {
+		t.Run(testCases[i].Name, func(t *testing.T) {
+			vm := NewResponseVM[*someStruct]().SetErrorFromError(testCases[i].Err)
+
+			if vm.Code != testCases[i].ExpectedCode {
+				t.Errorf("expected code is %d, got %d", testCases[i].ExpectedCode, vm.Code)
+			}
+
+			if testCases[i].ExpectedMessage != "" && vm.Error.Message != testCases[i].ExpectedMessage {
+				t.Errorf("expected message is %s, got %s", testCases[i].ExpectedMessage, vm.Error.Message)
+			}
+		})
+	}
+}
+
+func TestContextError(t *testing.T) {
+	if ContextError(context.Background()) != nil {
+		t.Error("expected nil error for a live context")
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	if err := ContextError(ctx); err != context.Canceled {
+		t.Errorf("expected error is %v, got %v", context.Canceled, err)
+	}
+
+	errShutdown := errors.New("shutting down")
+	ctx, cancelCause := context.WithCancelCause(context.Background())
+	cancelCause(errShutdown)
+
+	err := ContextError(ctx)
+	if !errors.Is(err, errShutdown) || !errors.Is(err, context.Canceled) {
+		t.Errorf("expected error to match both cause and context.Canceled, got %v", err)
+	}
+}
+
+// TestRenderer_ClientGone tests that nothing is written to a disconnected client
+func TestRenderer_ClientGone(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest(http.MethodGet, "/", nil).WithContext(ctx)
+
+	err := NewRenderer().Render(w, r, NewResponseVM[*someStruct]().SetErrorFromError(ContextError(ctx)))
+	if err != context.Canceled {
+		t.Errorf("expected error is %v, got %v", context.Canceled, err)
+	}
+
+	if w.Body.Len() != 0 {
+		t.Errorf("expected empty body, got %s", w.Body.String())
+	}
+}
@@ -0,0 +1,261 @@
+package gores
+
+import (
+	"bytes"
+	"compress/gzip"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/base64"
+	"fmt"
+	"hash"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// DigestAlgorithm is a hash algorithm of the RFC 9530 Content-Digest field.
+type DigestAlgorithm string
+
+const (
+	DigestSHA256 DigestAlgorithm = "sha-256" // SHA-256
+	DigestSHA512 DigestAlgorithm = "sha-512" // SHA-512
+)
+
+// newHash returns a hash of the algorithm, or nil when it is not supported.
+func (a DigestAlgorithm) newHash() hash.Hash {
+	switch a {
+	case DigestSHA256:
+		return sha256.New()
+	case DigestSHA512:
+		return sha512.New()
+	}
+
+	return nil
+}
+
+// IntegrityError reports a response whose content does not match its Content-Digest.
+type IntegrityError struct {
+	Algorithm    DigestAlgorithm // Algorithm whose digest did not match, empty when no digest was usable
+	Expected     []byte          // Digest announced by the server
+	Actual       []byte          // Digest of the received content
+	Uncompressed bool            // Whether the transport decompressed the body, so the digest could not be checked
+}
+
+// Error describes the mismatch with both digests in base64.
+func (e *IntegrityError) Error() string {
+	if e.Uncompressed {
+		return "gores: Content-Digest cannot be verified on a body the transport decompressed"
+	}
+
+	if e.Algorithm == "" {
+		return "gores: response carries no supported Content-Digest"
+	}
+
+	return fmt.Sprintf("gores: Content-Digest %s mismatch: expected %s, got %s", e.Algorithm,
+		base64.StdEncoding.EncodeToString(e.Expected), base64.StdEncoding.EncodeToString(e.Actual))
+}
+
+// contentDigest formats the Content-Digest field value of content, e.g. "sha-256=:...:".
+// Unsupported algorithms are skipped.
+func contentDigest(content []byte, algorithms []DigestAlgorithm) string {
+	var supported []DigestAlgorithm
+	var hashes []hash.Hash
+	for _, algorithm := range algorithms {
+		if h := algorithm.newHash(); h != nil {
+			h.Write(content)
+			supported = append(supported, algorithm)
+			hashes = append(hashes, h)
+		}
+	}
+
+	return formatDigest(supported, hashes)
+}
+
+// formatDigest formats the sum of each hash under the algorithm at the same index.
+func formatDigest(algorithms []DigestAlgorithm, hashes []hash.Hash) string {
+	members := make([]string, len(hashes))
+	for i, h := range hashes {
+		members[i] = string(algorithms[i]) + "=:" + base64.StdEncoding.EncodeToString(h.Sum(nil)) + ":"
+	}
+
+	return strings.Join(members, ", ")
+}
+
+// parseContentDigest parses a Content-Digest dictionary into digests by algorithm.
+// Members with unsupported algorithms or malformed values are ignored.
+func parseContentDigest(value string) map[DigestAlgorithm][]byte {
+	digests := make(map[DigestAlgorithm][]byte)
+	for _, member := range strings.Split(value, ",") {
+		name, encoded, found := strings.Cut(strings.TrimSpace(member), "=")
+		if !found {
+			continue
+		}
+
+		// Parameters after the byte sequence carry no meaning for verification
+		encoded, _, _ = strings.Cut(encoded, ";")
+		if len(encoded) < 2 || encoded[0] != ':' || encoded[len(encoded)-1] != ':' {
+			continue
+		}
+
+		algorithm := DigestAlgorithm(strings.ToLower(name))
+		if algorithm.newHash() == nil {
+			continue
+		}
+
+		if digest, err := base64.StdEncoding.DecodeString(encoded[1 : len(encoded)-1]); err == nil {
+			digests[algorithm] = digest
+		}
+	}
+
+	return digests
+}
+
+// VerifyContentDigest reads the body of resp and checks it against the Content-Digest
+// header or, for streamed responses, trailer. Every supported digest must match. The
+// body is restored so it can still be decoded. It returns an *IntegrityError when a
+// digest does not match or none is present, and when the transport decompressed the
+// body transparently, since the digest covers the compressed bytes. Responses without
+// a body, such as 204, 304 and HEAD responses, are not checked.
+func VerifyContentDigest(resp *http.Response) error {
+	if !hasResponseBody(resp) {
+		return nil
+	}
+
+	if resp.Uncompressed {
+		return &IntegrityError{Uncompressed: true}
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return err
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+
+	// Trailers are only known once the body was read to the end
+	value := resp.Header.Get("Content-Digest")
+	if value == "" {
+		value = resp.Trailer.Get("Content-Digest")
+	}
+
+	digests := parseContentDigest(value)
+	if len(digests) == 0 {
+		return &IntegrityError{}
+	}
+
+	for _, algorithm := range []DigestAlgorithm{DigestSHA256, DigestSHA512} {
+		expected, exists := digests[algorithm]
+		if !exists {
+			continue
+		}
+
+		h := algorithm.newHash()
+		h.Write(body)
+		if actual := h.Sum(nil); !bytes.Equal(actual, expected) {
+			return &IntegrityError{Algorithm: algorithm, Expected: expected, Actual: actual}
+		}
+	}
+
+	return nil
+}
+
+// decompressGzip replaces the gzip-coded body of resp with the decoded content, as
+// http.Transport does when it negotiated the coding itself.
+func decompressGzip(resp *http.Response) error {
+	if !hasResponseBody(resp) || !strings.EqualFold(resp.Header.Get("Content-Encoding"), "gzip") {
+		return nil
+	}
+
+	reader, err := gzip.NewReader(resp.Body)
+	if err != nil {
+		return err
+	}
+
+	body, err := io.ReadAll(reader)
+	resp.Body.Close()
+	if err != nil {
+		return err
+	}
+
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+	resp.Header.Del("Content-Encoding")
+	resp.Header.Del("Content-Length")
+	resp.ContentLength = -1
+	resp.Uncompressed = true
+
+	return nil
+}
+
+// DigestWriter streams a response body while hashing it, and sends the RFC 9530
+// Content-Digest as a trailer once the body is complete. Create it before writing
+// the status code so the trailer can be announced, and call Close when done.
+type DigestWriter struct {
+	http.ResponseWriter
+	algorithms  []DigestAlgorithm
+	hashes      []hash.Hash
+	wroteHeader bool
+}
+
+// NewDigestWriter wraps w and announces the Content-Digest trailer.
+// Unsupported algorithms are ignored; without any, SHA-256 is used.
+func NewDigestWriter(w http.ResponseWriter, algorithms ...DigestAlgorithm) *DigestWriter {
+	dw := &DigestWriter{ResponseWriter: w}
+	for _, algorithm := range algorithms {
+		if h := algorithm.newHash(); h != nil {
+			dw.algorithms = append(dw.algorithms, algorithm)
+			dw.hashes = append(dw.hashes, h)
+		}
+	}
+
+	if len(dw.algorithms) == 0 {
+		dw.algorithms = []DigestAlgorithm{DigestSHA256}
+		dw.hashes = []hash.Hash{sha256.New()}
+	}
+
+	w.Header().Add("Trailer", "Content-Digest")
+	return dw
+}
+
+// WriteHeader writes the status code. A Content-Length is removed because trailers
+// can only follow a chunked body.
+func (dw *DigestWriter) WriteHeader(code int) {
+	if !dw.wroteHeader && code >= http.StatusOK {
+		dw.wroteHeader = true
+		dw.Header().Del("Content-Length")
+	}
+
+	dw.ResponseWriter.WriteHeader(code)
+}
+
+// Write writes p to the response and adds it to the digest.
+func (dw *DigestWriter) Write(p []byte) (int, error) {
+	if !dw.wroteHeader {
+		dw.WriteHeader(http.StatusOK)
+	}
+
+	n, err := dw.ResponseWriter.Write(p)
+	for _, h := range dw.hashes {
+		h.Write(p[:n])
+	}
+
+	return n, err
+}
+
+// Flush sends buffered data to the client when the underlying writer supports it.
+func (dw *DigestWriter) Flush() {
+	if flusher, ok := dw.ResponseWriter.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
+
+// Close sets the Content-Digest trailer of everything written so far.
+// Nothing may be written after Close.
+func (dw *DigestWriter) Close() error {
+	dw.Header().Set("Content-Digest", formatDigest(dw.algorithms, dw.hashes))
+	return nil
+}
+
+// Unwrap returns the underlying writer for http.ResponseController.
+func (dw *DigestWriter) Unwrap() http.ResponseWriter {
+	return dw.ResponseWriter
+}
@@ -0,0 +1,220 @@
+package gores
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestContentDigest(t *testing.T) {
+	testCases := []struct {
+		Name       string
+		Algorithms []DigestAlgorithm
+		Expected   string
+	}{
+		{
+			Name:       "SHA256",
+			Algorithms: []DigestAlgorithm{DigestSHA256},
+			Expected:   "sha-256=:X48E9qOokqqrvdts8nOJRJN3OWDUoyWxBf7kbu9DBPE=:",
+		},
+		{
+			Name:       "SHA512AndUnsupported",
+			Algorithms: []DigestAlgorithm{"md5", DigestSHA512},
+			Expected:   "sha-512=:WZDPaVn/7XgHaAy8pmojAkGWoRx2UFChF41A2svX+TaPm+AbwAgBWnrIiYllu7BNNyealdVLvRwEmTHWXvJwew==:",
+		},
+	}
+
+	for i := range testCases {
+		t.Run(testCases[i].Name, func(t *testing.T) {
+			actual := contentDigest([]byte(`{"hello": "world"}`), testCases[i].Algorithms)
+			if actual != testCases[i].Expected {
+				t.Errorf("expected digest is %s, got %s", testCases[i].Expected, actual)
+			}
+		})
+	}
+}
+
+func TestVerifyContentDigest(t *testing.T) {
+	const body = `{"hello": "world"}`
+	const sha256Digest = "sha-256=:X48E9qOokqqrvdts8nOJRJN3OWDUoyWxBf7kbu9DBPE=:"
+
+	testCases := []struct {
+		Name          string
+		Code          int
+		Method        string
+		Header        string
+		Trailer       string
+		Body          string
+		Uncompressed  bool
+		ExpectedError bool
+	}{
+		{Name: "Header", Header: sha256Digest, Body: body},
+		{Name: "Trailer", Trailer: sha256Digest, Body: body},
+		{Name: "UnknownAlgorithmIgnored", Header: "md5=:AAAA:, " + sha256Digest, Body: body},
+		{Name: "Mismatch", Header: sha256Digest, Body: `{"hello": "there"}`, ExpectedError: true},
+		{Name: "Missing", Body: body, ExpectedError: true},
+		{Name: "Malformed", Header: "sha-256=X48E9qOokqqrvdts8nOJRJN3OWDUoyWxBf7kbu9DBPE=", Body: body, ExpectedError: true},
+		{Name: "TransportDecompressed", Header: sha256Digest, Body: body, Uncompressed: true, ExpectedError: true},
+		{Name: "NoContent", Code: http.StatusNoContent},
+		{Name: "Head", Method: http.MethodHead, Header: sha256Digest},
+	}
+
+	for i := range testCases {
+		t.Run(testCases[i].Name, func(t *testing.T) {
+			code := testCases[i].Code
+			if code == 0 {
+				code = http.StatusOK
+			}
+
+			method := testCases[i].Method
+			if method == "" {
+				method = http.MethodGet
+			}
+
+			resp := &http.Response{
+				StatusCode: code,
+				Header:     http.Header{},
+				Trailer:    http.Header{},
+				Body:       io.NopCloser(strings.NewReader(testCases[i].Body)),
+				Request:    httptest.NewRequest(method, "/", nil),
+
+				Uncompressed: testCases[i].Uncompressed,
+			}
+			if testCases[i].Header != "" {
+				resp.Header.Set("Content-Digest", testCases[i].Header)
+			}
+			if testCases[i].Trailer != "" {
+				resp.Trailer.Set("Content-Digest", testCases[i].Trailer)
+			}
+
+			err := VerifyContentDigest(resp)
+
+			var integrityErr *IntegrityError
+			if errors.As(err, &integrityErr) != testCases[i].ExpectedError {
+				t.Fatalf("expected integrity error is %t, got %v", testCases[i].ExpectedError, err)
+			}
+
+			restored, _ := io.ReadAll(resp.Body)
+			if string(restored) != testCases[i].Body {
+				t.Errorf("expected restored body is %s, got %s", testCases[i].Body, restored)
+			}
+		})
+	}
+}
+
+func TestRenderer_SetContentDigest(t *testing.T) {
+	recorder := httptest.NewRecorder()
+	NewRenderer().
+		SetContentDigest(DigestSHA256, DigestSHA512).
+		Render(recorder, nil, NewResponseVM[*someStruct]().SetCode(http.StatusOK).SetData(&someStruct{SomeField: "value"}))
+
+	expected := contentDigest(recorder.Body.Bytes(), []DigestAlgorithm{DigestSHA256, DigestSHA512})
+	if actual := recorder.Header().Get("Content-Digest"); actual != expected || !strings.HasPrefix(actual, "sha-256=:") {
+		t.Errorf("expected Content-Digest is %s, got %s", expected, actual)
+	}
+}
+
+func TestClient_SetVerifyDigest(t *testing.T) {
+	corrupt := false
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// Streamed through a DigestWriter, so the digest arrives as a trailer
+		dw := NewDigestWriter(w, DigestSHA512)
+		defer dw.Close()
+
+		NewRenderer().Render(dw, r, NewResponseVM[*someStruct]().SetCode(http.StatusOK).SetData(&someStruct{SomeField: "value"}))
+		dw.Flush()
+
+		if corrupt {
+			// Extra bytes written outside the digest
+			dw.ResponseWriter.Write([]byte(" "))
+		}
+	}))
+	defer server.Close()
+
+	client := NewClient(server.Client()).SetVerifyDigest(true)
+
+	req, _ := http.NewRequest(http.MethodGet, server.URL, nil)
+	resp, err := client.Do(req)
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+
+	if resp.Header.Get("Content-Digest") != "" || !strings.HasPrefix(resp.Trailer.Get("Content-Digest"), "sha-512=:") {
+		t.Errorf("expected Content-Digest trailer, got header %q and trailer %q", resp.Header.Get("Content-Digest"), resp.Trailer.Get("Content-Digest"))
+	}
+
+	vm, err := DecodeResponse[*someStruct](resp)
+	if err != nil || vm.Data.SomeField != "value" {
+		t.Errorf("expected decoded data, got %+v, %v", vm, err)
+	}
+
+	corrupt = true
+	req, _ = http.NewRequest(http.MethodGet, server.URL, nil)
+	_, err = client.Do(req)
+
+	var integrityErr *IntegrityError
+	if !errors.As(err, &integrityErr) || integrityErr.Algorithm != DigestSHA512 {
+		t.Errorf("expected integrity error, got %v", err)
+	}
+}
+
+// tamperDigestWriter replaces the Content-Digest of a response with the one of another body
+type tamperDigestWriter struct {
+	http.ResponseWriter
+}
+
+// WriteHeader replaces the Content-Digest and writes the status code
+func (w *tamperDigestWriter) WriteHeader(code int) {
+	w.Header().Set("Content-Digest", "sha-256=:X48E9qOokqqrvdts8nOJRJN3OWDUoyWxBf7kbu9DBPE=:")
+	w.ResponseWriter.WriteHeader(code)
+}
+
+func TestClient_SetVerifyDigest_Compressed(t *testing.T) {
+	tamper := false
+	var acceptEncoding string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		acceptEncoding = r.Header.Get("Accept-Encoding")
+		if tamper {
+			w = &tamperDigestWriter{ResponseWriter: w}
+		}
+
+		NewRenderer().
+			SetCompression(NewCompression().SetThreshold(0)).
+			SetContentDigest(DigestSHA256).
+			Render(w, r, NewResponseVM[*someStruct]().SetCode(http.StatusOK).SetData(&someStruct{SomeField: "value"}))
+	}))
+	defer server.Close()
+
+	client := NewClient(server.Client()).SetVerifyDigest(true)
+
+	req, _ := http.NewRequest(http.MethodGet, server.URL, nil)
+	resp, err := client.Do(req)
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+
+	if acceptEncoding != "gzip" || req.Header.Get("Accept-Encoding") != "" {
+		t.Errorf("expected client to request gzip without modifying req, got %q and %q", acceptEncoding, req.Header.Get("Accept-Encoding"))
+	}
+
+	if !resp.Uncompressed || resp.Header.Get("Content-Encoding") != "" {
+		t.Errorf("expected decompressed body, got Content-Encoding %q", resp.Header.Get("Content-Encoding"))
+	}
+
+	vm, err := DecodeResponse[*someStruct](resp)
+	if err != nil || vm.Data.SomeField != "value" {
+		t.Errorf("expected decoded data, got %+v, %v", vm, err)
+	}
+
+	tamper = true
+	req, _ = http.NewRequest(http.MethodGet, server.URL, nil)
+	_, err = client.Do(req)
+
+	var integrityErr *IntegrityError
+	if !errors.As(err, &integrityErr) || integrityErr.Algorithm != DigestSHA256 {
+		t.Errorf("expected integrity error, got %v", err)
+	}
+}
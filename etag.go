@@ -0,0 +1,292 @@
+package gores
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/fikri240794/gocerr"
+)
+
+// ETagMode controls how a Renderer derives an ETag for successful responses.
+// A caller-supplied version set via ResponseVM.SetVersion always takes precedence
+// over a computed one; the mode then only decides whether it is weak or strong.
+type ETagMode int
+
+const (
+	ETagNone   ETagMode = iota // Do not compute an ETag from the body
+	ETagStrong                 // Compute a strong ETag from the encoded envelope
+	ETagWeak                   // Compute a weak ETag from the encoded envelope
+)
+
+// conditionResult is the outcome of evaluating a single conditional header.
+type conditionResult int
+
+const (
+	conditionNone  conditionResult = iota // Header absent or not applicable
+	conditionTrue                         // Condition holds
+	conditionFalse                        // Condition fails
+)
+
+// NewETag formats a version string as an HTTP entity tag.
+// Values that are already entity tags are returned unchanged so callers
+// can pass either bare versions or previously issued ETags.
+func NewETag(version string, weak bool) string {
+	if version == "" {
+		return ""
+	}
+
+	// Keep values that are already quoted entity tags
+	if etag, remain := scanETag(version); etag != "" && remain == "" {
+		return etag
+	}
+
+	etag := `"` + strings.ReplaceAll(version, `"`, "") + `"`
+	if weak {
+		etag = "W/" + etag
+	}
+
+	return etag
+}
+
+// computeETag derives an entity tag from an encoded response body.
+// A truncated SHA-256 digest keeps the header short while remaining collision resistant.
+func computeETag(body []byte, weak bool) string {
+	sum := sha256.Sum256(body)
+	return NewETag(base64.RawURLEncoding.EncodeToString(sum[:16]), weak)
+}
+
+// CheckPreconditions evaluates If-Match and If-Unmodified-Since for state-changing requests.
+// Handlers call it with the current version of the resource before applying a write, either
+// as an entity tag or as a bare version such as "42", which is compared as the strong tag "42".
+// It returns a gocerr.Error with HTTP 412 Precondition Failed when a precondition does not hold,
+// ready to be passed to ResponseVM.SetErrorFromError, or nil when the write may proceed.
+// Use Renderer.CheckPreconditions when the Renderer compresses responses.
+func CheckPreconditions(r *http.Request, etag string, lastModified time.Time) error {
+	return checkPreconditions(r, etag, lastModified, "")
+}
+
+// CheckPreconditions evaluates preconditions like the package-level CheckPreconditions, also
+// accepting the tag the Renderer issues for the content coding negotiated for r, such as "42-gzip".
+func (rd *Renderer) CheckPreconditions(r *http.Request, etag string, lastModified time.Time) error {
+	return checkPreconditions(r, etag, lastModified, rd.compression.negotiate(r))
+}
+
+// checkPreconditions evaluates preconditions, matching If-Match against the tag of the
+// uncompressed representation and, when coding is set, against the tag suffixed for coding.
+func checkPreconditions(r *http.Request, etag string, lastModified time.Time, coding string) error {
+	if r == nil {
+		return nil
+	}
+
+	etag = NewETag(etag, false)
+
+	// If-Match takes precedence; If-Unmodified-Since is only evaluated in its absence
+	if checkIfMatch(r, etag, coding) == conditionFalse {
+		return gocerr.New(
+			http.StatusPreconditionFailed,
+			"precondition failed: the resource has been modified since it was last retrieved (If-Match did not match the current ETag)",
+		)
+	}
+
+	if r.Header.Get("If-Match") == "" && checkIfUnmodifiedSince(r, lastModified) == conditionFalse {
+		return gocerr.New(
+			http.StatusPreconditionFailed,
+			"precondition failed: the resource has been modified since "+r.Header.Get("If-Unmodified-Since"),
+		)
+	}
+
+	return nil
+}
+
+// preconditionRequiredMessage is the default message for writes missing a precondition.
+const preconditionRequiredMessage = "precondition required: include If-Match with the current ETag of the resource"
+
+// RequirePreconditions rejects state-changing requests that carry neither If-Match nor If-Unmodified-Since.
+// It returns a gocerr.Error with HTTP 428 Precondition Required, or nil when a precondition is present.
+// Combine it with CheckPreconditions to enforce optimistic concurrency on every write.
+func RequirePreconditions(r *http.Request) error {
+	if r == nil || r.Header.Get("If-Match") != "" || r.Header.Get("If-Unmodified-Since") != "" {
+		return nil
+	}
+
+	return gocerr.New(http.StatusPreconditionRequired, preconditionRequiredMessage)
+}
+
+// isNotModified reports whether a safe request can be answered with 304 Not Modified.
+// If-None-Match takes precedence over If-Modified-Since as required by RFC 9110.
+func isNotModified(r *http.Request, etag string, lastModified time.Time) bool {
+	if r == nil || (r.Method != http.MethodGet && r.Method != http.MethodHead) {
+		return false
+	}
+
+	switch checkIfNoneMatch(r, etag) {
+	case conditionFalse:
+		return true
+	case conditionTrue:
+		return false
+	}
+
+	return checkIfModifiedSince(r, lastModified) == conditionFalse
+}
+
+// checkIfMatch evaluates If-Match using strong comparison. Tags suffixed for coding
+// identify the same version, since they only differ in their content coding.
+func checkIfMatch(r *http.Request, etag, coding string) conditionResult {
+	header := r.Header.Get("If-Match")
+	if header == "" {
+		return conditionNone
+	}
+
+	for {
+		header = trimHTTPSpace(header)
+		if header == "" {
+			break
+		}
+
+		if header[0] == ',' {
+			header = header[1:]
+			continue
+		}
+
+		// A wildcard matches any current representation
+		if header[0] == '*' {
+			if etag != "" {
+				return conditionTrue
+			}
+			return conditionFalse
+		}
+
+		candidate, remain := scanETag(header)
+		if candidate == "" {
+			break
+		}
+
+		if etagStrongMatch(candidate, etag) || (coding != "" && etagStrongMatch(candidate, encodedETag(etag, coding))) {
+			return conditionTrue
+		}
+
+		header = remain
+	}
+
+	return conditionFalse
+}
+
+// checkIfNoneMatch evaluates If-None-Match using weak comparison.
+// conditionFalse means a listed tag matched and the representation is unchanged.
+func checkIfNoneMatch(r *http.Request, etag string) conditionResult {
+	header := r.Header.Get("If-None-Match")
+	if header == "" {
+		return conditionNone
+	}
+
+	for {
+		header = trimHTTPSpace(header)
+		if header == "" {
+			break
+		}
+
+		if header[0] == ',' {
+			header = header[1:]
+			continue
+		}
+
+		if header[0] == '*' {
+			if etag != "" {
+				return conditionFalse
+			}
+			return conditionTrue
+		}
+
+		candidate, remain := scanETag(header)
+		if candidate == "" {
+			break
+		}
+
+		if etagWeakMatch(candidate, etag) {
+			return conditionFalse
+		}
+
+		header = remain
+	}
+
+	return conditionTrue
+}
+
+// checkIfModifiedSince evaluates If-Modified-Since at one-second resolution.
+func checkIfModifiedSince(r *http.Request, lastModified time.Time) conditionResult {
+	return checkTimeCondition(r.Header.Get("If-Modified-Since"), lastModified, false)
+}
+
+// checkIfUnmodifiedSince evaluates If-Unmodified-Since at one-second resolution.
+func checkIfUnmodifiedSince(r *http.Request, lastModified time.Time) conditionResult {
+	return checkTimeCondition(r.Header.Get("If-Unmodified-Since"), lastModified, true)
+}
+
+// checkTimeCondition compares lastModified against an HTTP date header.
+// With unmodified set the condition holds when the resource is not newer than the date,
+// otherwise it holds when the resource is newer than the date.
+func checkTimeCondition(header string, lastModified time.Time, unmodified bool) conditionResult {
+	if header == "" || lastModified.IsZero() {
+		return conditionNone
+	}
+
+	since, err := http.ParseTime(header)
+	if err != nil {
+		return conditionNone
+	}
+
+	// HTTP dates carry no sub-second precision
+	modified := lastModified.Truncate(time.Second).After(since)
+	if modified != unmodified {
+		return conditionTrue
+	}
+
+	return conditionFalse
+}
+
+// scanETag extracts the first entity tag from s and returns the remainder.
+// It returns an empty tag when s does not start with a valid entity tag.
+func scanETag(s string) (etag string, remain string) {
+	s = trimHTTPSpace(s)
+
+	start := 0
+	if strings.HasPrefix(s, "W/") {
+		start = 2
+	}
+
+	if len(s[start:]) < 2 || s[start] != '"' {
+		return "", ""
+	}
+
+	// Characters allowed in an opaque tag are %x21 / %x23-7E / obs-text
+	for i := start + 1; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case c == 0x21 || (c >= 0x23 && c <= 0x7E) || c >= 0x80:
+		case c == '"':
+			return s[:i+1], s[i+1:]
+		default:
+			return "", ""
+		}
+	}
+
+	return "", ""
+}
+
+// etagStrongMatch reports whether two entity tags match using strong comparison.
+func etagStrongMatch(a, b string) bool {
+	return a == b && a != "" && a[0] == '"'
+}
+
+// etagWeakMatch reports whether two entity tags match using weak comparison.
+func etagWeakMatch(a, b string) bool {
+	return a != "" && strings.TrimPrefix(a, "W/") == strings.TrimPrefix(b, "W/")
+}
+
+// trimHTTPSpace trims leading and trailing HTTP whitespace.
+func trimHTTPSpace(s string) string {
+	return strings.Trim(s, " \t")
+}
@@ -0,0 +1,159 @@
+package gores
+
+import (
+	"compress/gzip"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/fikri240794/gocerr"
+)
+
+func TestNewETag(t *testing.T) {
+	testCases := []struct {
+		Name     string
+		Version  string
+		Weak     bool
+		Expected string
+	}{
+		{Name: "Empty", Version: "", Expected: ""},
+		{Name: "Strong", Version: "42", Expected: `"42"`},
+		{Name: "Weak", Version: "42", Weak: true, Expected: `W/"42"`},
+		{Name: "AlreadyQuoted", Version: `"42"`, Weak: true, Expected: `"42"`},
+		{Name: "AlreadyWeak", Version: `W/"42"`, Expected: `W/"42"`},
+	}
+
+	for i := range testCases {
+		t.Run(testCases[i].Name, func(t *testing.T) {
+			actual := NewETag(testCases[i].Version, testCases[i].Weak)
+			if actual != testCases[i].Expected {
+				t.Errorf("expected etag is %s, got %s", testCases[i].Expected, actual)
+			}
+		})
+	}
+}
+
+func TestCheckPreconditions(t *testing.T) {
+	lastModified := time.Date(2024, time.January, 2, 3, 4, 5, 0, time.UTC)
+
+	testCases := []struct {
+		Name         string
+		Headers      map[string]string
+		ETag         string
+		ExpectedCode int
+	}{
+		{Name: "NoHeaders", ETag: `"v1"`},
+		{Name: "IfMatchMatches", Headers: map[string]string{"If-Match": `"v0", "v1"`}, ETag: `"v1"`},
+		{Name: "IfMatchBareVersion", Headers: map[string]string{"If-Match": `"42"`}, ETag: "42"},
+		{Name: "IfMatchBareVersionMismatch", Headers: map[string]string{"If-Match": `"41"`}, ETag: "42", ExpectedCode: http.StatusPreconditionFailed},
+		{Name: "IfMatchEncodedTagNeedsRenderer", Headers: map[string]string{"If-Match": `"42-gzip"`}, ETag: "42", ExpectedCode: http.StatusPreconditionFailed},
+		{Name: "IfMatchMismatch", Headers: map[string]string{"If-Match": `"v0"`}, ETag: `"v1"`, ExpectedCode: http.StatusPreconditionFailed},
+		{Name: "IfMatchWeakNeverMatches", Headers: map[string]string{"If-Match": `W/"v1"`}, ETag: `W/"v1"`, ExpectedCode: http.StatusPreconditionFailed},
+		{Name: "IfMatchWildcard", Headers: map[string]string{"If-Match": "*"}, ETag: `"v1"`},
+		{Name: "IfMatchWildcardMissingResource", Headers: map[string]string{"If-Match": "*"}, ExpectedCode: http.StatusPreconditionFailed},
+		{Name: "IfUnmodifiedSinceHolds", Headers: map[string]string{"If-Unmodified-Since": lastModified.Format(http.TimeFormat)}},
+		{Name: "IfUnmodifiedSinceFails", Headers: map[string]string{"If-Unmodified-Since": lastModified.Add(-time.Hour).Format(http.TimeFormat)}, ExpectedCode: http.StatusPreconditionFailed},
+		{
+			Name: "IfMatchTakesPrecedence",
+			Headers: map[string]string{
+				"If-Match":            `"v1"`,
+				"If-Unmodified-Since": lastModified.Add(-time.Hour).Format(http.TimeFormat),
+			},
+			ETag: `"v1"`,
+		},
+	}
+
+	for i := range testCases {
+		t.Run(testCases[i].Name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodPut, "/", nil)
+			for key, value := range testCases[i].Headers {
+				r.Header.Set(key, value)
+			}
+
+			err := CheckPreconditions(r, testCases[i].ETag, lastModified)
+			if code := gocerr.GetErrorCode(err); code != testCases[i].ExpectedCode {
+				t.Errorf("expected code is %d, got %d", testCases[i].ExpectedCode, code)
+			}
+		})
+	}
+}
+
+func TestRenderer_CheckPreconditions(t *testing.T) {
+	compressed := NewRenderer().SetCompression(NewCompression())
+	brotli := NewRenderer().SetCompression(NewCompression().SetEncoder("br", func(w io.Writer) (io.WriteCloser, error) {
+		return gzip.NewWriter(w), nil
+	}))
+
+	testCases := []struct {
+		Name           string
+		Renderer       *Renderer
+		AcceptEncoding string
+		IfMatch        string
+		ExpectedCode   int
+	}{
+		{Name: "IdentityTag", Renderer: compressed, AcceptEncoding: "gzip", IfMatch: `"42"`},
+		{Name: "NegotiatedCoding", Renderer: compressed, AcceptEncoding: "gzip", IfMatch: `"42-gzip"`},
+		{Name: "VersionMismatch", Renderer: compressed, AcceptEncoding: "gzip", IfMatch: `"41-gzip"`, ExpectedCode: http.StatusPreconditionFailed},
+		{Name: "OtherCoding", Renderer: compressed, AcceptEncoding: "gzip", IfMatch: `"42-deflate"`, ExpectedCode: http.StatusPreconditionFailed},
+		{Name: "NotAccepted", Renderer: compressed, IfMatch: `"42-gzip"`, ExpectedCode: http.StatusPreconditionFailed},
+		{Name: "UnknownSuffix", Renderer: compressed, AcceptEncoding: "gzip", IfMatch: `"42-draft"`, ExpectedCode: http.StatusPreconditionFailed},
+		{Name: "CustomCoding", Renderer: brotli, AcceptEncoding: "br", IfMatch: `"42-br"`},
+		{Name: "CustomCodingOfOtherRenderer", Renderer: compressed, AcceptEncoding: "br", IfMatch: `"42-br"`, ExpectedCode: http.StatusPreconditionFailed},
+		{Name: "WithoutCompression", Renderer: NewRenderer(), AcceptEncoding: "gzip", IfMatch: `"42-gzip"`, ExpectedCode: http.StatusPreconditionFailed},
+	}
+
+	for i := range testCases {
+		t.Run(testCases[i].Name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodPut, "/", nil)
+			r.Header.Set("Accept-Encoding", testCases[i].AcceptEncoding)
+			r.Header.Set("If-Match", testCases[i].IfMatch)
+
+			err := testCases[i].Renderer.CheckPreconditions(r, "42", time.Time{})
+			if code := gocerr.GetErrorCode(err); code != testCases[i].ExpectedCode {
+				t.Errorf("expected code is %d, got %d", testCases[i].ExpectedCode, code)
+			}
+		})
+	}
+}
+
+func TestIsNotModified(t *testing.T) {
+	lastModified := time.Date(2024, time.January, 2, 3, 4, 5, 0, time.UTC)
+
+	testCases := []struct {
+		Name     string
+		Method   string
+		Headers  map[string]string
+		Expected bool
+	}{
+		{Name: "NoHeaders", Method: http.MethodGet},
+		{Name: "IfNoneMatchMatches", Method: http.MethodGet, Headers: map[string]string{"If-None-Match": `"v0", W/"v1"`}, Expected: true},
+		{Name: "IfNoneMatchMismatch", Method: http.MethodGet, Headers: map[string]string{"If-None-Match": `"v0"`}},
+		{Name: "IfNoneMatchWildcard", Method: http.MethodHead, Headers: map[string]string{"If-None-Match": "*"}, Expected: true},
+		{Name: "UnsafeMethod", Method: http.MethodPost, Headers: map[string]string{"If-None-Match": `"v1"`}},
+		{Name: "IfModifiedSinceUnchanged", Method: http.MethodGet, Headers: map[string]string{"If-Modified-Since": lastModified.Format(http.TimeFormat)}, Expected: true},
+		{Name: "IfModifiedSinceChanged", Method: http.MethodGet, Headers: map[string]string{"If-Modified-Since": lastModified.Add(-time.Hour).Format(http.TimeFormat)}},
+		{
+			Name:   "IfNoneMatchTakesPrecedence",
+			Method: http.MethodGet,
+			Headers: map[string]string{
+				"If-None-Match":     `"v0"`,
+				"If-Modified-Since": lastModified.Format(http.TimeFormat),
+			},
+		},
+	}
+
+	for i := range testCases {
+		t.Run(testCases[i].Name, func(t *testing.T) {
+			r := httptest.NewRequest(testCases[i].Method, "/", nil)
+			for key, value := range testCases[i].Headers {
+				r.Header.Set(key, value)
+			}
+
+			if actual := isNotModified(r, `"v1"`, lastModified); actual != testCases[i].Expected {
+				t.Errorf("expected not modified is %t, got %t", testCases[i].Expected, actual)
+			}
+		})
+	}
+}
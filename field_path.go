@@ -0,0 +1,315 @@
+package gores
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// FieldPathFormat selects how field paths are written in error_fields.
+type FieldPathFormat int
+
+const (
+	FieldPathRaw         FieldPathFormat = iota // Field written exactly as set (default)
+	FieldPathDotted                             // items.0.price
+	FieldPathBracket                            // items[0].price
+	FieldPathJSONPointer                        // /items/0/price (RFC 6901)
+)
+
+// PathSegment is a single step of a FieldPath: an object key or an array index.
+type PathSegment struct {
+	Key     string // Object member name, empty for index segments
+	Index   int    // Array index, only meaningful for index segments
+	IsIndex bool   // Whether the segment is an array index
+}
+
+// FieldPath is the canonical model of the location of a field inside a request.
+// It is rendered with Dotted, Bracket or JSONPointer and parsed back with ParseFieldPath,
+// so that field errors do not depend on ad-hoc string conventions.
+type FieldPath []PathSegment
+
+// NewFieldPath creates a path from keys and indexes, e.g. NewFieldPath("items", 0, "price").
+// Strings become key segments and ints become index segments; other values are ignored.
+func NewFieldPath(segments ...interface{}) FieldPath {
+	path := make(FieldPath, 0, len(segments))
+	for _, segment := range segments {
+		switch s := segment.(type) {
+		case string:
+			path = path.Key(s)
+		case int:
+			path = path.Index(s)
+		}
+	}
+
+	return path
+}
+
+// ParseFieldPath parses a dotted ("items.0.price"), bracket ("items[0].price") or
+// JSON Pointer ("/items/0/price") path. Numeric dotted and pointer segments are read
+// as indexes, since those notations cannot tell them apart from keys.
+func ParseFieldPath(s string) FieldPath {
+	if s == "" {
+		return FieldPath{}
+	}
+
+	if strings.HasPrefix(s, "/") {
+		return parseJSONPointer(s)
+	}
+
+	path := FieldPath{}
+	var key strings.Builder
+
+	flush := func() {
+		if key.Len() > 0 {
+			path = path.appendParsed(key.String())
+			key.Reset()
+		}
+	}
+
+	for i := 0; i < len(s); i++ {
+		switch s[i] {
+		case '.':
+			flush()
+		case '[':
+			flush()
+
+			end := strings.IndexByte(s[i:], ']')
+			if end < 0 {
+				key.WriteString(s[i:])
+				i = len(s)
+				continue
+			}
+
+			inner := s[i+1 : i+end]
+			if unquoted, err := strconv.Unquote(inner); err == nil {
+				path = path.Key(unquoted)
+			} else if len(inner) > 1 && inner[0] == '\'' && inner[len(inner)-1] == '\'' {
+				path = path.Key(inner[1 : len(inner)-1])
+			} else {
+				path = path.appendParsed(inner)
+			}
+
+			i += end
+		default:
+			key.WriteByte(s[i])
+		}
+	}
+
+	flush()
+	return path
+}
+
+// parseJSONPointer parses an RFC 6901 JSON Pointer.
+func parseJSONPointer(s string) FieldPath {
+	tokens := strings.Split(s[1:], "/")
+
+	path := make(FieldPath, 0, len(tokens))
+	for _, token := range tokens {
+		path = path.appendParsed(strings.NewReplacer("~1", "/", "~0", "~").Replace(token))
+	}
+
+	return path
+}
+
+// appendParsed appends a segment read from a string, treating non-negative integers as indexes.
+func (p FieldPath) appendParsed(s string) FieldPath {
+	if index, err := strconv.Atoi(s); err == nil && index >= 0 && s == strconv.Itoa(index) {
+		return p.Index(index)
+	}
+
+	return p.Key(s)
+}
+
+// Key returns a copy of the path extended with an object key.
+func (p FieldPath) Key(key string) FieldPath {
+	return append(p[:len(p):len(p)], PathSegment{Key: key})
+}
+
+// Index returns a copy of the path extended with an array index.
+func (p FieldPath) Index(index int) FieldPath {
+	return append(p[:len(p):len(p)], PathSegment{Index: index, IsIndex: true})
+}
+
+// Dotted renders the path as "items.0.price".
+func (p FieldPath) Dotted() string {
+	parts := make([]string, 0, len(p))
+	for _, segment := range p {
+		parts = append(parts, segment.String())
+	}
+
+	return strings.Join(parts, ".")
+}
+
+// Bracket renders the path as "items[0].price". Keys that are not plain identifiers
+// are quoted, e.g. `meta["content-type"]`.
+func (p FieldPath) Bracket() string {
+	var b strings.Builder
+	for i, segment := range p {
+		switch {
+		case segment.IsIndex:
+			b.WriteString("[" + strconv.Itoa(segment.Index) + "]")
+		case !isPathIdentifier(segment.Key):
+			b.WriteString("[" + strconv.Quote(segment.Key) + "]")
+		default:
+			if i > 0 {
+				b.WriteByte('.')
+			}
+			b.WriteString(segment.Key)
+		}
+	}
+
+	return b.String()
+}
+
+// JSONPointer renders the path as an RFC 6901 JSON Pointer, e.g. "/items/0/price".
+func (p FieldPath) JSONPointer() string {
+	var b strings.Builder
+	for _, segment := range p {
+		b.WriteByte('/')
+		b.WriteString(strings.NewReplacer("~", "~0", "/", "~1").Replace(segment.String()))
+	}
+
+	return b.String()
+}
+
+// Format renders the path in the given format. FieldPathRaw renders it dotted.
+func (p FieldPath) Format(format FieldPathFormat) string {
+	switch format {
+	case FieldPathBracket:
+		return p.Bracket()
+	case FieldPathJSONPointer:
+		return p.JSONPointer()
+	}
+
+	return p.Dotted()
+}
+
+// String renders the path dotted.
+func (p FieldPath) String() string {
+	return p.Dotted()
+}
+
+// String renders the segment as its key or decimal index.
+func (s PathSegment) String() string {
+	if s.IsIndex {
+		return strconv.Itoa(s.Index)
+	}
+
+	return s.Key
+}
+
+// isPathIdentifier reports whether key can be written unquoted in a bracket path.
+func isPathIdentifier(key string) bool {
+	if key == "" {
+		return false
+	}
+
+	for i, c := range key {
+		switch {
+		case c == '_' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z'):
+		case c >= '0' && c <= '9' && i > 0:
+		default:
+			return false
+		}
+	}
+
+	return true
+}
+
+// SetFieldPath sets the field from a path model, written dotted. An EnvelopeSchema
+// can re-render it as a bracket path or JSON Pointer; see EnvelopeSchema.SetFieldPathFormat.
+func (vm *ResponseErrorFieldVM) SetFieldPath(path FieldPath) *ResponseErrorFieldVM {
+	vm.Field = path.Dotted()
+	return vm
+}
+
+// fieldErrorsTreeKey holds the messages of a path that also has nested field errors.
+const fieldErrorsTreeKey = "_errors"
+
+// ErrorFieldsTree renders the field errors as nested objects keyed by path segment, with
+// the messages of each field as a list, e.g. {"items":{"0":{"price":["must be positive"]}}}.
+// When a field has both messages and nested errors, its messages are kept under "_errors".
+func (vm *ResponseErrorVM) ErrorFieldsTree() map[string]interface{} {
+	tree := make(map[string]interface{})
+
+	for _, errorField := range vm.ErrorFields {
+		if errorField == nil {
+			continue
+		}
+
+		path := ParseFieldPath(errorField.Field)
+		if len(path) == 0 {
+			path = FieldPath{}.Key("")
+		}
+
+		node := tree
+		for _, segment := range path[:len(path)-1] {
+			node = treeChild(node, segment.String())
+		}
+
+		leaf := path[len(path)-1].String()
+		switch existing := node[leaf].(type) {
+		case map[string]interface{}:
+			messages, _ := existing[fieldErrorsTreeKey].([]string)
+			existing[fieldErrorsTreeKey] = append(messages, errorField.Message)
+		case []string:
+			node[leaf] = append(existing, errorField.Message)
+		default:
+			node[leaf] = []string{errorField.Message}
+		}
+	}
+
+	return tree
+}
+
+// treeChild returns the nested object under key, creating it or moving existing
+// messages under fieldErrorsTreeKey as needed.
+func treeChild(node map[string]interface{}, key string) map[string]interface{} {
+	switch existing := node[key].(type) {
+	case map[string]interface{}:
+		return existing
+	case []string:
+		child := map[string]interface{}{fieldErrorsTreeKey: existing}
+		node[key] = child
+		return child
+	}
+
+	child := make(map[string]interface{})
+	node[key] = child
+	return child
+}
+
+// errorFieldsFromTree flattens a tree produced by ErrorFieldsTree back into field errors
+// ordered by path, writing each path in the given format.
+func errorFieldsFromTree(tree map[string]interface{}, format FieldPathFormat) []*ResponseErrorFieldVM {
+	var errorFields []*ResponseErrorFieldVM
+
+	var walk func(node map[string]interface{}, path FieldPath)
+	walk = func(node map[string]interface{}, path FieldPath) {
+		keys := make([]string, 0, len(node))
+		for key := range node {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+
+		for _, key := range keys {
+			childPath := path.appendParsed(key)
+			if key == fieldErrorsTreeKey {
+				childPath = path
+			}
+
+			switch value := node[key].(type) {
+			case map[string]interface{}:
+				walk(value, childPath)
+			case []interface{}:
+				for _, message := range value {
+					text, _ := message.(string)
+					errorFields = append(errorFields, NewResponseErrorFieldVM(childPath.Format(format), text))
+				}
+			}
+		}
+	}
+
+	walk(tree, FieldPath{})
+	return errorFields
+}
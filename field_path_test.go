@@ -0,0 +1,92 @@
+package gores
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestParseFieldPath(t *testing.T) {
+	testCases := []struct {
+		Name        string
+		Input       string
+		Dotted      string
+		Bracket     string
+		JSONPointer string
+	}{
+		{Name: "empty", Input: "", Dotted: "", Bracket: "", JSONPointer: ""},
+		{Name: "single key", Input: "email", Dotted: "email", Bracket: "email", JSONPointer: "/email"},
+		{Name: "dotted", Input: "items.0.price", Dotted: "items.0.price", Bracket: "items[0].price", JSONPointer: "/items/0/price"},
+		{Name: "bracket", Input: "items[0].price", Dotted: "items.0.price", Bracket: "items[0].price", JSONPointer: "/items/0/price"},
+		{Name: "nested indexes", Input: "matrix[1][2]", Dotted: "matrix.1.2", Bracket: "matrix[1][2]", JSONPointer: "/matrix/1/2"},
+		{Name: "json pointer", Input: "/items/0/price", Dotted: "items.0.price", Bracket: "items[0].price", JSONPointer: "/items/0/price"},
+		{Name: "json pointer escapes", Input: "/headers/a~1b/c~0d", Dotted: "headers.a/b.c~d", Bracket: `headers["a/b"]["c~d"]`, JSONPointer: "/headers/a~1b/c~0d"},
+		{Name: "quoted bracket key", Input: `meta["content-type"]`, Dotted: "meta.content-type", Bracket: `meta["content-type"]`, JSONPointer: "/meta/content-type"},
+	}
+
+	for i := range testCases {
+		t.Run(testCases[i].Name, func(t *testing.T) {
+			path := ParseFieldPath(testCases[i].Input)
+
+			if actual := path.Dotted(); actual != testCases[i].Dotted {
+				t.Errorf("expected dotted path is %s, got %s", testCases[i].Dotted, actual)
+			}
+
+			if actual := path.Bracket(); actual != testCases[i].Bracket {
+				t.Errorf("expected bracket path is %s, got %s", testCases[i].Bracket, actual)
+			}
+
+			if actual := path.JSONPointer(); actual != testCases[i].JSONPointer {
+				t.Errorf("expected JSON pointer is %s, got %s", testCases[i].JSONPointer, actual)
+			}
+		})
+	}
+}
+
+func TestNewFieldPath(t *testing.T) {
+	base := NewFieldPath("items", 0)
+	price := base.Key("price")
+	quantity := base.Key("quantity")
+
+	if price.Bracket() != "items[0].price" || quantity.Bracket() != "items[0].quantity" {
+		t.Errorf("expected extended paths not to share segments, got %s and %s", price.Bracket(), quantity.Bracket())
+	}
+
+	field := NewResponseErrorFieldVM("", "must be positive").SetFieldPath(price)
+	if field.Field != "items.0.price" {
+		t.Errorf("expected field is %s, got %s", "items.0.price", field.Field)
+	}
+}
+
+func TestResponseErrorVM_ErrorFieldsTree(t *testing.T) {
+	vm := NewResponseErrorVM().AddErrorFields(
+		NewResponseErrorFieldVM("items[0].price", "must be positive"),
+		NewResponseErrorFieldVM("items.0.price", "must be a number"),
+		NewResponseErrorFieldVM("items.1.sku", "is required"),
+		NewResponseErrorFieldVM("items", "must not have duplicates"),
+		NewResponseErrorFieldVM("email", "is invalid"),
+	)
+
+	tree, err := json.Marshal(vm.ErrorFieldsTree())
+	if err != nil {
+		t.Fatalf("expected error is nil, got %v", err)
+	}
+
+	expected := `{"email":["is invalid"],"items":{"0":{"price":["must be positive","must be a number"]},"1":{"sku":["is required"]},"_errors":["must not have duplicates"]}}`
+	if string(tree) != expected {
+		t.Errorf("expected tree is %s, got %s", expected, string(tree))
+	}
+
+	var decoded map[string]interface{}
+	if err := json.Unmarshal(tree, &decoded); err != nil {
+		t.Fatalf("expected error is nil, got %v", err)
+	}
+
+	fields := errorFieldsFromTree(decoded, FieldPathBracket)
+	if len(fields) != 5 {
+		t.Fatalf("expected length of error fields is 5, got %d", len(fields))
+	}
+
+	if fields[1].Field != "items[0].price" || fields[4].Field != "items" {
+		t.Errorf("unexpected flattened fields %s and %s", fields[1].Field, fields[4].Field)
+	}
+}
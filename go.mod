@@ -1,5 +1,5 @@
 module github.com/fikri240794/gores
 
-go 1.18
+go 1.20
 
 require github.com/fikri240794/gocerr v0.0.4
@@ -0,0 +1,39 @@
+package gorestest
+
+import "testing"
+
+func TestDiff(t *testing.T) {
+	testCases := []struct {
+		Name     string
+		Expected string
+		Actual   string
+		Diff     string
+	}{
+		{
+			Name:     "Equal",
+			Expected: "a\nb",
+			Actual:   "a\nb",
+			Diff:     "",
+		},
+		{
+			Name:     "ChangedLine",
+			Expected: "{\n  \"name\": \"alice\"\n}",
+			Actual:   "{\n  \"name\": \"bob\"\n}",
+			Diff:     "  {\n-   \"name\": \"alice\"\n+   \"name\": \"bob\"\n  }\n",
+		},
+		{
+			Name:     "AddedAndRemovedLines",
+			Expected: "a\nb\nc",
+			Actual:   "b\nc\nd",
+			Diff:     "- a\n  b\n  c\n+ d\n",
+		},
+	}
+
+	for i := range testCases {
+		t.Run(testCases[i].Name, func(t *testing.T) {
+			if actual := Diff(testCases[i].Expected, testCases[i].Actual); actual != testCases[i].Diff {
+				t.Errorf("expected diff is\n%q\ngot\n%q", testCases[i].Diff, actual)
+			}
+		})
+	}
+}
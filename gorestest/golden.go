@@ -0,0 +1,151 @@
+package gorestest
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+)
+
+// update rewrites golden files with the actual output instead of comparing against them.
+// Run "go test ./... -update" after an intended change of the response format.
+var update = flag.Bool("update", false, "rewrite gorestest golden files with the actual output")
+
+// UpdateEnv is an environment variable that rewrites golden files like the -update flag,
+// e.g. "GORESTEST_UPDATE=1 go test ./...", where some packages do not import gorestest
+// and would reject the flag.
+const UpdateEnv = "GORESTEST_UPDATE"
+
+// goldenDir is the directory golden files are read from, relative to the package under test.
+var goldenDir = "testdata"
+
+// MaskedValue replaces the values of volatile members in canonical snapshots.
+const MaskedValue = "<masked>"
+
+// DefaultMaskedKeys are the members whose values change on every request and are
+// therefore masked wherever they appear in a snapshot.
+var DefaultMaskedKeys = []string{"request_id", "timestamp", "duration_ms"}
+
+// Canonical renders v as canonical JSON for snapshots: object keys sorted, two-space
+// indentation, a trailing newline, and the values of DefaultMaskedKeys and maskedKeys
+// replaced with MaskedValue at any depth. A []byte or json.RawMessage is taken as JSON.
+func Canonical(v interface{}, maskedKeys ...string) ([]byte, error) {
+	var raw []byte
+	switch typed := v.(type) {
+	case []byte:
+		raw = typed
+	case json.RawMessage:
+		raw = typed
+	default:
+		encoded, err := json.Marshal(v)
+		if err != nil {
+			return nil, err
+		}
+		raw = encoded
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(raw))
+	decoder.UseNumber()
+
+	var document interface{}
+	if err := decoder.Decode(&document); err != nil {
+		return nil, err
+	}
+
+	masked := make(map[string]bool, len(DefaultMaskedKeys)+len(maskedKeys))
+	for _, key := range append(append([]string{}, DefaultMaskedKeys...), maskedKeys...) {
+		masked[key] = true
+	}
+
+	var out bytes.Buffer
+	encoder := json.NewEncoder(&out)
+	encoder.SetEscapeHTML(false)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(maskValues(document, masked)); err != nil {
+		return nil, err
+	}
+
+	return out.Bytes(), nil
+}
+
+// maskValues replaces the values of masked object members, keeping nulls so that
+// a snapshot still shows whether the member was set.
+func maskValues(value interface{}, masked map[string]bool) interface{} {
+	switch typed := value.(type) {
+	case map[string]interface{}:
+		for key, member := range typed {
+			if masked[key] && member != nil {
+				typed[key] = MaskedValue
+			} else {
+				typed[key] = maskValues(member, masked)
+			}
+		}
+	case []interface{}:
+		for i, item := range typed {
+			typed[i] = maskValues(item, masked)
+		}
+	}
+
+	return value
+}
+
+// AssertGolden compares the canonical JSON of v with the golden file testdata/<name>.golden,
+// reporting a diff on mismatch. With the -update flag, or UpdateEnv set to true, the golden
+// file is written instead.
+// The name may contain slashes, so t.Name() of a subtest can be used directly.
+func AssertGolden(t testing.TB, name string, v interface{}, maskedKeys ...string) {
+	t.Helper()
+
+	actual, err := Canonical(v, maskedKeys...)
+	if err != nil {
+		t.Fatalf("gorestest: rendering snapshot %s: %v", name, err)
+	}
+
+	path := goldenPath(name)
+
+	if updateGolden() {
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatalf("gorestest: creating golden directory: %v", err)
+		}
+		if err := os.WriteFile(path, actual, 0o644); err != nil {
+			t.Fatalf("gorestest: writing golden file: %v", err)
+		}
+		t.Logf("gorestest: updated golden file %s", path)
+		return
+	}
+
+	expected, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		t.Fatalf("gorestest: golden file %s does not exist; run go test with -update to create it", path)
+	}
+	if err != nil {
+		t.Fatalf("gorestest: reading golden file: %v", err)
+	}
+
+	if !bytes.Equal(expected, actual) {
+		t.Errorf("gorestest: snapshot %s does not match %s (-golden +actual); run go test with -update if the change is intended:\n%s",
+			name, path, Diff(string(expected), string(actual)))
+	}
+}
+
+// AssertGolden compares the recorded response body with a golden file; see AssertGolden.
+func (r *Response) AssertGolden(name string, maskedKeys ...string) *Response {
+	r.t.Helper()
+
+	AssertGolden(r.t, name, r.Recorder.Body.Bytes(), maskedKeys...)
+	return r
+}
+
+// updateGolden reports whether the -update flag or UpdateEnv asks for golden files to be rewritten.
+func updateGolden() bool {
+	env, _ := strconv.ParseBool(os.Getenv(UpdateEnv))
+	return *update || env
+}
+
+// goldenPath returns the location of the golden file of a snapshot name.
+func goldenPath(name string) string {
+	return filepath.Join(goldenDir, filepath.FromSlash(name)+".golden")
+}
@@ -0,0 +1,146 @@
+package gorestest
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/fikri240794/gores"
+)
+
+func TestCanonical(t *testing.T) {
+	testCases := []struct {
+		Name       string
+		Value      interface{}
+		MaskedKeys []string
+		Expected   string
+	}{
+		{
+			Name:     "SortsKeys",
+			Value:    map[string]interface{}{"b": 1, "a": []int{1, 2}},
+			Expected: "{\n  \"a\": [\n    1,\n    2\n  ],\n  \"b\": 1\n}\n",
+		},
+		{
+			Name:     "RawJSONKeepsNumbers",
+			Value:    []byte(`{"id":12345678901234567890,"html":"<b>"}`),
+			Expected: "{\n  \"html\": \"<b>\",\n  \"id\": 12345678901234567890\n}\n",
+		},
+		{
+			Name: "MasksVolatileMembers",
+			Value: gores.NewResponseVM[string]().
+				SetCode(http.StatusOK).
+				SetData("ok").
+				SetMeta(gores.NewMetaVM().
+					SetRequestID("5f0c").
+					SetTimestamp(time.Now()).
+					SetDuration(3 * time.Millisecond).
+					SetAPIVersion("v1")),
+			Expected: "{\n  \"code\": 200,\n  \"data\": \"ok\",\n  \"meta\": {\n    \"api_version\": \"v1\",\n    \"duration_ms\": \"<masked>\",\n    \"request_id\": \"<masked>\",\n    \"timestamp\": \"<masked>\"\n  }\n}\n",
+		},
+		{
+			Name:       "MasksAdditionalKeysAtAnyDepth",
+			Value:      []byte(`{"data":[{"id":"a1","created_at":"2024-01-01"},{"id":"a2","created_at":null}]}`),
+			MaskedKeys: []string{"created_at"},
+			Expected:   "{\n  \"data\": [\n    {\n      \"created_at\": \"<masked>\",\n      \"id\": \"a1\"\n    },\n    {\n      \"created_at\": null,\n      \"id\": \"a2\"\n    }\n  ]\n}\n",
+		},
+	}
+
+	for i := range testCases {
+		t.Run(testCases[i].Name, func(t *testing.T) {
+			actual, err := Canonical(testCases[i].Value, testCases[i].MaskedKeys...)
+			if err != nil {
+				t.Fatalf("unexpected error %v", err)
+			}
+
+			if string(actual) != testCases[i].Expected {
+				t.Errorf("expected canonical JSON is\n%s\ngot\n%s", testCases[i].Expected, actual)
+			}
+		})
+	}
+}
+
+func TestResponse_AssertGolden(t *testing.T) {
+	Do(t, testHandler, httptest.NewRequest(http.MethodGet, "/invalid", nil)).
+		AssertGolden("invalid_user")
+}
+
+func TestAssertGolden(t *testing.T) {
+	testCases := []struct {
+		Name      string
+		Golden    string
+		Update    bool
+		UpdateEnv bool
+		Expected  []string
+		Written   string
+	}{
+		{
+			Name:    "Match",
+			Golden:  "{\n  \"code\": 200\n}\n",
+			Written: "{\n  \"code\": 200\n}\n",
+		},
+		{
+			Name:     "Mismatch",
+			Golden:   "{\n  \"code\": 201\n}\n",
+			Expected: []string{"gorestest: snapshot users/create does not match " + filepath.Join("users", "create.golden") + " (-golden +actual); run go test with -update if the change is intended:\n  {\n-   \"code\": 201\n+   \"code\": 200\n  }\n  \n"},
+			Written:  "{\n  \"code\": 201\n}\n",
+		},
+		{
+			Name:     "Missing",
+			Expected: []string{"gorestest: golden file " + filepath.Join("users", "create.golden") + " does not exist; run go test with -update to create it"},
+		},
+		{
+			Name:    "Update",
+			Golden:  "{\n  \"code\": 201\n}\n",
+			Update:  true,
+			Written: "{\n  \"code\": 200\n}\n",
+		},
+		{
+			Name:      "UpdateEnv",
+			Golden:    "{\n  \"code\": 201\n}\n",
+			UpdateEnv: true,
+			Written:   "{\n  \"code\": 200\n}\n",
+		},
+	}
+
+	for i := range testCases {
+		t.Run(testCases[i].Name, func(t *testing.T) {
+			dir := t.TempDir()
+			t.Setenv(UpdateEnv, strconv.FormatBool(testCases[i].UpdateEnv))
+
+			previousDir, previousUpdate := goldenDir, *update
+			goldenDir, *update = dir, testCases[i].Update
+			defer func() { goldenDir, *update = previousDir, previousUpdate }()
+
+			path := filepath.Join(dir, "users", "create.golden")
+			if testCases[i].Golden != "" {
+				if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+					t.Fatal(err)
+				}
+				if err := os.WriteFile(path, []byte(testCases[i].Golden), 0o644); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			fake := &fakeT{}
+			func() {
+				defer func() { recover() }()
+				AssertGolden(fake, "users/create", map[string]int{"code": 200})
+			}()
+
+			actual := strings.ReplaceAll(strings.Join(fake.failures, "\n---\n"), dir+string(filepath.Separator), "")
+			if actual != strings.Join(testCases[i].Expected, "\n---\n") {
+				t.Errorf("expected failures are\n%q\ngot\n%q", strings.Join(testCases[i].Expected, "\n---\n"), actual)
+			}
+
+			written, _ := os.ReadFile(path)
+			if string(written) != testCases[i].Written {
+				t.Errorf("expected golden file is\n%s\ngot\n%s", testCases[i].Written, written)
+			}
+		})
+	}
+}
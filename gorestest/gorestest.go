@@ -0,0 +1,281 @@
+// Package gorestest provides assertion helpers for testing HTTP handlers that respond
+// with gores envelopes. Handlers are run through httptest, the envelope is decoded
+// generically and assertions report readable failures with diffs.
+package gorestest
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/fikri240794/gores"
+)
+
+// Response is the recorded outcome of running a handler, with its decoded envelope.
+// Assertion methods report failures on the test and return the Response for chaining.
+type Response struct {
+	t        testing.TB
+	Recorder *httptest.ResponseRecorder          // Raw recorded response
+	Envelope *gores.ResponseVM[*json.RawMessage] // Decoded envelope with undecoded data
+}
+
+// Do runs handler with req through httptest and decodes the gores envelope.
+// The test fails immediately when the body is not a gores envelope.
+func Do(t testing.TB, handler http.Handler, req *http.Request) *Response {
+	t.Helper()
+	return DoWithSchema(t, handler, req, nil)
+}
+
+// DoWithSchema is like Do for handlers rendering with a custom EnvelopeSchema.
+// A nil schema decodes the default envelope shape.
+func DoWithSchema(t testing.TB, handler http.Handler, req *http.Request, schema *gores.EnvelopeSchema) *Response {
+	t.Helper()
+
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, req)
+
+	envelope, err := gores.DecodeResponseWithSchema[*json.RawMessage](recorder.Result(), schema)
+	if err != nil {
+		t.Fatalf("gorestest: %s %s: decoding envelope: %v\nbody: %s", req.Method, req.URL.Path, err, recorder.Body.String())
+	}
+
+	return &Response{t: t, Recorder: recorder, Envelope: envelope}
+}
+
+// AssertStatus checks the HTTP status code and, when present in the body, the envelope code.
+func (r *Response) AssertStatus(code int) *Response {
+	r.t.Helper()
+
+	if r.Recorder.Code != code {
+		r.t.Errorf("gorestest: expected HTTP status %d %s, got %d %s%s",
+			code, http.StatusText(code), r.Recorder.Code, http.StatusText(r.Recorder.Code), r.errorSummary())
+	}
+
+	if r.Envelope.Code != code {
+		r.t.Errorf("gorestest: expected envelope code %d, got %d", code, r.Envelope.Code)
+	}
+
+	return r
+}
+
+// DecodeData decodes the envelope data into target, failing the test when it cannot.
+func (r *Response) DecodeData(target interface{}) *Response {
+	r.t.Helper()
+
+	if r.Envelope.Data == nil {
+		r.t.Fatalf("gorestest: expected data, got none%s", r.errorSummary())
+	}
+
+	if err := json.Unmarshal(*r.Envelope.Data, target); err != nil {
+		r.t.Fatalf("gorestest: decoding data into %T: %v", target, err)
+	}
+
+	return r
+}
+
+// AssertData checks that the envelope data deeply equals expected once both are encoded
+// as JSON, so expected may be a struct, a map or any other value with the same encoding.
+// On mismatch a line diff of the indented JSON is reported.
+func (r *Response) AssertData(expected interface{}) *Response {
+	r.t.Helper()
+
+	expectedJSON, err := json.Marshal(expected)
+	if err != nil {
+		r.t.Fatalf("gorestest: encoding expected data: %v", err)
+	}
+
+	actualJSON := []byte("null")
+	if r.Envelope.Data != nil {
+		actualJSON = *r.Envelope.Data
+	}
+
+	var expectedValue, actualValue interface{}
+	_ = json.Unmarshal(expectedJSON, &expectedValue)
+	_ = json.Unmarshal(actualJSON, &actualValue)
+
+	if !reflect.DeepEqual(expectedValue, actualValue) {
+		r.t.Errorf("gorestest: data mismatch (-expected +actual):\n%s", Diff(indentJSON(expectedJSON), indentJSON(actualJSON)))
+	}
+
+	return r
+}
+
+// AssertNoError checks that the envelope carries no error.
+func (r *Response) AssertNoError() *Response {
+	r.t.Helper()
+
+	if r.Envelope.Error != nil {
+		r.t.Errorf("gorestest: expected no error, got%s", r.errorSummary())
+	}
+
+	return r
+}
+
+// AssertErrorMessage checks the message of the envelope error.
+func (r *Response) AssertErrorMessage(message string) *Response {
+	r.t.Helper()
+
+	if !r.requireError() {
+		return r
+	}
+
+	if r.Envelope.Error.Message != message {
+		r.t.Errorf("gorestest: expected error message %q, got %q", message, r.Envelope.Error.Message)
+	}
+
+	return r
+}
+
+// AssertErrorCode checks the machine-readable code of the envelope error.
+func (r *Response) AssertErrorCode(errorCode string) *Response {
+	r.t.Helper()
+
+	if !r.requireError() {
+		return r
+	}
+
+	if r.Envelope.Error.ErrorCode != errorCode {
+		r.t.Errorf("gorestest: expected error code %q, got %q", errorCode, r.Envelope.Error.ErrorCode)
+	}
+
+	return r
+}
+
+// AssertErrorFields checks that the field errors match expected exactly, in any order.
+// Members left empty in an expected field error match any value; see AssertErrorFieldsContain.
+func (r *Response) AssertErrorFields(expected ...*gores.ResponseErrorFieldVM) *Response {
+	r.t.Helper()
+	return r.assertErrorFields(expected, true)
+}
+
+// AssertErrorFieldsContain checks that every expected field error is present, in any order,
+// ignoring additional ones. Members left empty in an expected field error match any value,
+// so NewResponseErrorFieldVM("email", "") only requires an error on email.
+func (r *Response) AssertErrorFieldsContain(expected ...*gores.ResponseErrorFieldVM) *Response {
+	r.t.Helper()
+	return r.assertErrorFields(expected, false)
+}
+
+// assertErrorFields matches expected field errors against the actual ones.
+func (r *Response) assertErrorFields(expected []*gores.ResponseErrorFieldVM, exact bool) *Response {
+	r.t.Helper()
+
+	if !r.requireError() {
+		return r
+	}
+
+	actual := r.Envelope.Error.ErrorFields
+	used := make([]bool, len(actual))
+
+	var missing []string
+	for _, want := range expected {
+		found := false
+		for i, got := range actual {
+			if !used[i] && got != nil && matchErrorField(want, got) {
+				used[i], found = true, true
+				break
+			}
+		}
+
+		if !found {
+			missing = append(missing, formatErrorField(want))
+		}
+	}
+
+	var unexpected []string
+	if exact {
+		for i, got := range actual {
+			if !used[i] && got != nil {
+				unexpected = append(unexpected, formatErrorField(got))
+			}
+		}
+	}
+
+	if len(missing) > 0 || len(unexpected) > 0 {
+		var b strings.Builder
+		b.WriteString("gorestest: error fields mismatch")
+		for _, field := range missing {
+			b.WriteString("\n  missing:    " + field)
+		}
+		for _, field := range unexpected {
+			b.WriteString("\n  unexpected: " + field)
+		}
+		b.WriteString("\n  actual:")
+		for _, field := range actual {
+			if field != nil {
+				b.WriteString("\n    " + formatErrorField(field))
+			}
+		}
+		r.t.Error(b.String())
+	}
+
+	return r
+}
+
+// requireError reports a failure when the envelope carries no error.
+func (r *Response) requireError() bool {
+	r.t.Helper()
+
+	if r.Envelope.Error == nil {
+		r.t.Errorf("gorestest: expected an error, got none (HTTP status %d)", r.Recorder.Code)
+		return false
+	}
+
+	return true
+}
+
+// errorSummary describes the envelope error for failure messages.
+func (r *Response) errorSummary() string {
+	if r.Envelope.Error == nil {
+		return ""
+	}
+
+	summary := fmt.Sprintf(" (error: %q", r.Envelope.Error.Message)
+	for _, field := range r.Envelope.Error.ErrorFields {
+		if field != nil {
+			summary += ", " + formatErrorField(field)
+		}
+	}
+
+	return summary + ")"
+}
+
+// matchErrorField reports whether got matches want, ignoring members empty in want.
+func matchErrorField(want, got *gores.ResponseErrorFieldVM) bool {
+	return want.Field == got.Field &&
+		(want.Message == "" || want.Message == got.Message) &&
+		(want.Location == "" || want.Location == got.Location) &&
+		(want.Code == "" || want.Code == got.Code)
+}
+
+// formatErrorField renders a field error on a single line.
+func formatErrorField(field *gores.ResponseErrorFieldVM) string {
+	formatted := field.Field + ": " + field.Message
+	if field.Location != "" {
+		formatted += " [location=" + string(field.Location) + "]"
+	}
+	if field.Code != "" {
+		formatted += " [code=" + field.Code + "]"
+	}
+
+	return formatted
+}
+
+// indentJSON formats a JSON document with sorted keys and two-space indentation.
+func indentJSON(raw []byte) string {
+	var value interface{}
+	if err := json.Unmarshal(raw, &value); err != nil {
+		return string(raw)
+	}
+
+	sorted, _ := json.Marshal(value)
+
+	var out bytes.Buffer
+	_ = json.Indent(&out, sorted, "", "  ")
+	return out.String()
+}
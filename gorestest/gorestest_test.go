@@ -0,0 +1,160 @@
+package gorestest
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/fikri240794/gocerr"
+	"github.com/fikri240794/gores"
+)
+
+// fakeT records failures instead of failing the surrounding test.
+type fakeT struct {
+	testing.TB
+	failures []string
+}
+
+func (f *fakeT) Helper() {}
+
+func (f *fakeT) Error(args ...interface{}) {
+	f.failures = append(f.failures, fmt.Sprint(args...))
+}
+
+func (f *fakeT) Errorf(format string, args ...interface{}) {
+	f.failures = append(f.failures, fmt.Sprintf(format, args...))
+}
+
+func (f *fakeT) Logf(format string, args ...interface{}) {}
+
+func (f *fakeT) Fatalf(format string, args ...interface{}) {
+	f.failures = append(f.failures, fmt.Sprintf(format, args...))
+	panic("fatal")
+}
+
+type user struct {
+	ID    int      `json:"id"`
+	Name  string   `json:"name"`
+	Roles []string `json:"roles"`
+}
+
+var testHandler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+	renderer := gores.NewRenderer()
+
+	if r.URL.Path == "/invalid" {
+		renderer.RenderError(w, r, gocerr.New(
+			http.StatusUnprocessableEntity,
+			"validation failed",
+			gocerr.NewErrorField("name", "name is required"),
+			gocerr.NewErrorField("email", "email is invalid"),
+		))
+		return
+	}
+
+	renderer.Render(w, r, gores.NewResponseVM[*user]().
+		SetCode(http.StatusOK).
+		SetData(&user{ID: 1, Name: "alice", Roles: []string{"admin"}}))
+})
+
+func TestResponse_Success(t *testing.T) {
+	var decoded user
+
+	Do(t, testHandler, httptest.NewRequest(http.MethodGet, "/users/1", nil)).
+		AssertStatus(http.StatusOK).
+		AssertNoError().
+		AssertData(&user{ID: 1, Name: "alice", Roles: []string{"admin"}}).
+		AssertData(map[string]interface{}{"id": 1, "name": "alice", "roles": []string{"admin"}}).
+		DecodeData(&decoded)
+
+	if decoded.Name != "alice" {
+		t.Errorf("expected decoded name is alice, got %s", decoded.Name)
+	}
+}
+
+func TestResponse_Failures(t *testing.T) {
+	testCases := []struct {
+		Name     string
+		Path     string
+		Assert   func(r *Response)
+		Expected []string
+	}{
+		{
+			Name:   "Status",
+			Path:   "/invalid",
+			Assert: func(r *Response) { r.AssertStatus(http.StatusOK) },
+			Expected: []string{
+				`gorestest: expected HTTP status 200 OK, got 422 Unprocessable Entity (error: "validation failed", name: name is required, email: email is invalid)`,
+				"gorestest: expected envelope code 200, got 422",
+			},
+		},
+		{
+			Name:     "Data",
+			Path:     "/users/1",
+			Assert:   func(r *Response) { r.AssertData(&user{ID: 1, Name: "bob", Roles: []string{"admin"}}) },
+			Expected: []string{"gorestest: data mismatch (-expected +actual):\n  {\n    \"id\": 1,\n-   \"name\": \"bob\",\n+   \"name\": \"alice\",\n    \"roles\": [\n      \"admin\"\n    ]\n  }\n"},
+		},
+		{
+			Name:     "MissingError",
+			Path:     "/users/1",
+			Assert:   func(r *Response) { r.AssertErrorMessage("validation failed") },
+			Expected: []string{"gorestest: expected an error, got none (HTTP status 200)"},
+		},
+		{
+			Name:     "ErrorMessage",
+			Path:     "/invalid",
+			Assert:   func(r *Response) { r.AssertErrorMessage("invalid request").AssertErrorCode("") },
+			Expected: []string{`gorestest: expected error message "invalid request", got "validation failed"`},
+		},
+		{
+			Name: "ErrorFieldsExact",
+			Path: "/invalid",
+			Assert: func(r *Response) {
+				r.AssertErrorFields(gores.NewResponseErrorFieldVM("email", "email is invalid"), gores.NewResponseErrorFieldVM("age", ""))
+			},
+			Expected: []string{"gorestest: error fields mismatch\n  missing:    age: \n  unexpected: name: name is required\n  actual:\n    name: name is required\n    email: email is invalid"},
+		},
+		{
+			Name: "ErrorFieldsMatchInAnyOrder",
+			Path: "/invalid",
+			Assert: func(r *Response) {
+				r.AssertErrorFields(gores.NewResponseErrorFieldVM("email", "email is invalid"), gores.NewResponseErrorFieldVM("name", ""))
+			},
+		},
+		{
+			Name: "ErrorFieldsSubset",
+			Path: "/invalid",
+			Assert: func(r *Response) {
+				r.AssertErrorFieldsContain(gores.NewResponseErrorFieldVM("email", ""))
+			},
+		},
+	}
+
+	for i := range testCases {
+		t.Run(testCases[i].Name, func(t *testing.T) {
+			fake := &fakeT{}
+			testCases[i].Assert(Do(fake, testHandler, httptest.NewRequest(http.MethodGet, testCases[i].Path, nil)))
+
+			if strings.Join(fake.failures, "\n---\n") != strings.Join(testCases[i].Expected, "\n---\n") {
+				t.Errorf("expected failures are\n%s\ngot\n%s", strings.Join(testCases[i].Expected, "\n---\n"), strings.Join(fake.failures, "\n---\n"))
+			}
+		})
+	}
+}
+
+func TestDo_InvalidEnvelope(t *testing.T) {
+	fake := &fakeT{}
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("not json"))
+	})
+
+	func() {
+		defer func() { recover() }()
+		Do(fake, handler, httptest.NewRequest(http.MethodGet, "/", nil))
+	}()
+
+	if len(fake.failures) != 1 || !strings.HasPrefix(fake.failures[0], "gorestest: GET /: decoding envelope:") {
+		t.Errorf("unexpected failures %q", fake.failures)
+	}
+}
@@ -0,0 +1,389 @@
+package gores
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/fikri240794/gocerr"
+)
+
+// IdempotencyRecord is the stored state of a request identified by an Idempotency-Key.
+// A record without Completed set belongs to a request that is still in flight.
+type IdempotencyRecord struct {
+	Fingerprint string      // Hash identifying the original request
+	Completed   bool        // Whether the response has been captured
+	Code        int         // Captured HTTP status code
+	Header      http.Header // Captured response headers
+	Body        []byte      // Captured response body
+}
+
+// IdempotencyStore persists idempotency records between retries.
+// Implementations must be safe for concurrent use and make Reserve atomic,
+// so that only one of several concurrent duplicates proceeds to the handler.
+type IdempotencyStore interface {
+	// Reserve claims key for a new in-flight request. When the key is already known it
+	// returns the existing record and false without modifying it.
+	Reserve(ctx context.Context, key string, record *IdempotencyRecord, ttl time.Duration) (*IdempotencyRecord, bool, error)
+	// Save stores the completed record for key.
+	Save(ctx context.Context, key string, record *IdempotencyRecord, ttl time.Duration) error
+	// Delete forgets key so the request can be retried from scratch.
+	Delete(ctx context.Context, key string) error
+}
+
+// idempotencyStoreTimeout bounds store calls made once the response was written.
+const idempotencyStoreTimeout = 10 * time.Second
+
+// defaultIdempotencyMaxBodySize is the default limit on bodies read for fingerprinting.
+const defaultIdempotencyMaxBodySize = 1 << 20
+
+// Idempotency is a middleware that replays stored responses for retried requests
+// carrying the same Idempotency-Key header. Concurrent duplicates receive a 409 gores
+// error and keys reused with a different request receive a 422 gores error.
+type Idempotency struct {
+	store       IdempotencyStore
+	renderer    *Renderer
+	ttl         time.Duration
+	maxBodySize int64
+	fingerprint func(r *http.Request) (string, error)
+	reportFunc  func(r *http.Request, err error)
+}
+
+// NewIdempotency creates a new Idempotency middleware backed by store.
+// Records are kept for 24 hours and requests are fingerprinted by method, URL and body,
+// reading at most 1 MiB of body.
+func NewIdempotency(store IdempotencyStore) *Idempotency {
+	return &Idempotency{
+		store:       store,
+		renderer:    NewRenderer(),
+		ttl:         24 * time.Hour,
+		maxBodySize: defaultIdempotencyMaxBodySize,
+		fingerprint: fingerprintRequest,
+	}
+}
+
+// SetTTL sets how long completed responses are kept for replay.
+// This method uses method chaining pattern for fluent API design.
+func (m *Idempotency) SetTTL(ttl time.Duration) *Idempotency {
+	m.ttl = ttl
+	return m
+}
+
+// SetRenderer sets the Renderer used for idempotency error responses.
+// This method uses method chaining pattern for fluent API design.
+func (m *Idempotency) SetRenderer(renderer *Renderer) *Idempotency {
+	m.renderer = renderer
+	return m
+}
+
+// SetMaxBodySize limits the body of requests carrying an Idempotency-Key, which the
+// default fingerprint reads into memory. Larger bodies are rejected with 413; 0 disables the limit.
+func (m *Idempotency) SetMaxBodySize(maxBodySize int64) *Idempotency {
+	m.maxBodySize = maxBodySize
+	return m
+}
+
+// SetReportFunc sets a function notified of store errors, e.g. to log them. Clients only
+// receive a generic 503 gores error, so that store details are never exposed.
+func (m *Idempotency) SetReportFunc(report func(r *http.Request, err error)) *Idempotency {
+	m.reportFunc = report
+	return m
+}
+
+// SetFingerprintFunc sets the function that identifies a request payload.
+// Two requests with the same key but different fingerprints are rejected with 422.
+func (m *Idempotency) SetFingerprintFunc(fingerprint func(r *http.Request) (string, error)) *Idempotency {
+	m.fingerprint = fingerprint
+	return m
+}
+
+// Handler wraps next with idempotency handling.
+// Requests without an Idempotency-Key header and safe methods pass through unchanged.
+// Server errors (5xx) are not stored so that clients can retry them, and neither are
+// responses the store fails to save, whose key is released instead.
+func (m *Idempotency) Handler(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		key := r.Header.Get("Idempotency-Key")
+		if key == "" || isSafeMethod(r.Method) {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		if m.maxBodySize > 0 && r.Body != nil && r.Body != http.NoBody {
+			r.Body = http.MaxBytesReader(w, r.Body, m.maxBodySize)
+		}
+
+		fingerprint, err := m.fingerprint(r)
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			m.renderer.RenderError(w, r, gocerr.New(
+				http.StatusRequestEntityTooLarge,
+				fmt.Sprintf("request body exceeds %d bytes", tooLarge.Limit),
+			))
+			return
+		} else if err != nil {
+			m.renderer.RenderError(w, r, gocerr.New(http.StatusBadRequest, "unable to read request body: "+err.Error()))
+			return
+		}
+
+		existing, reserved, err := m.store.Reserve(r.Context(), key, &IdempotencyRecord{Fingerprint: fingerprint}, m.ttl)
+		if err != nil {
+			m.report(r, err)
+			m.renderer.RenderError(w, r, gocerr.New(
+				http.StatusServiceUnavailable,
+				"idempotency keys are temporarily unavailable",
+			))
+			return
+		}
+
+		if !reserved {
+			m.serveExisting(w, r, existing, fingerprint)
+			return
+		}
+
+		// Release the key if the handler panics or the response cannot be stored,
+		// so the client can retry
+		completed := false
+		defer func() {
+			if !completed {
+				ctx, cancel := detachedStoreContext()
+				defer cancel()
+				if err := m.store.Delete(ctx, key); err != nil {
+					m.report(r, err)
+				}
+			}
+		}()
+
+		capture := newCaptureWriter(w)
+		next.ServeHTTP(capture, r)
+
+		// A handler that writes nothing sends an implicit 200 with an empty body
+		if !capture.wroteHeader {
+			capture.code = http.StatusOK
+			capture.header = w.Header().Clone()
+		}
+
+		if capture.code >= http.StatusInternalServerError {
+			return
+		}
+
+		// The response was already sent, so saving must not be cancelled by the client leaving
+		ctx, cancel := detachedStoreContext()
+		defer cancel()
+
+		err = m.store.Save(ctx, key, &IdempotencyRecord{
+			Fingerprint: fingerprint,
+			Completed:   true,
+			Code:        capture.code,
+			Header:      capture.header,
+			Body:        capture.body.Bytes(),
+		}, m.ttl)
+		if err != nil {
+			m.report(r, err)
+		}
+		completed = err == nil
+	})
+}
+
+// report passes a store error to the report function, if any.
+func (m *Idempotency) report(r *http.Request, err error) {
+	if m.reportFunc != nil {
+		m.reportFunc(r, err)
+	}
+}
+
+// detachedStoreContext returns a context for store calls made after the response was
+// written, which the request context could otherwise cancel half-way.
+func detachedStoreContext() (context.Context, context.CancelFunc) {
+	return context.WithTimeout(context.Background(), idempotencyStoreTimeout)
+}
+
+// serveExisting answers a request whose key is already known to the store.
+func (m *Idempotency) serveExisting(w http.ResponseWriter, r *http.Request, record *IdempotencyRecord, fingerprint string) {
+	if record.Fingerprint != fingerprint {
+		m.renderer.RenderError(w, r, gocerr.New(
+			http.StatusUnprocessableEntity,
+			"Idempotency-Key has already been used with a different request payload",
+		))
+		return
+	}
+
+	if !record.Completed {
+		m.renderer.RenderError(w, r, gocerr.New(
+			http.StatusConflict,
+			"a request with the same Idempotency-Key is still being processed",
+		))
+		return
+	}
+
+	header := w.Header()
+	for key, values := range record.Header {
+		header[key] = append([]string(nil), values...)
+	}
+	header.Set("Idempotent-Replayed", "true")
+
+	w.WriteHeader(record.Code)
+	w.Write(record.Body)
+}
+
+// fingerprintRequest hashes the method, URL and body of a request.
+// The body is restored so the wrapped handler can read it again.
+func fingerprintRequest(r *http.Request) (string, error) {
+	hash := sha256.New()
+	io.WriteString(hash, r.Method+" "+r.URL.RequestURI()+"\n")
+
+	if r.Body != nil && r.Body != http.NoBody {
+		body, err := io.ReadAll(r.Body)
+		r.Body.Close()
+		if err != nil {
+			return "", err
+		}
+
+		r.Body = io.NopCloser(bytes.NewReader(body))
+		hash.Write(body)
+	}
+
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+// isSafeMethod reports whether the method is read-only per RFC 9110.
+func isSafeMethod(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodTrace:
+		return true
+	}
+
+	return false
+}
+
+// captureWriter writes a response through to the client while keeping a copy of it.
+type captureWriter struct {
+	http.ResponseWriter
+	code        int
+	header      http.Header
+	body        bytes.Buffer
+	wroteHeader bool
+}
+
+// newCaptureWriter creates a captureWriter wrapping w.
+func newCaptureWriter(w http.ResponseWriter) *captureWriter {
+	return &captureWriter{ResponseWriter: w}
+}
+
+// WriteHeader records the status code and a snapshot of the headers.
+func (cw *captureWriter) WriteHeader(code int) {
+	if cw.wroteHeader {
+		return
+	}
+
+	cw.wroteHeader = true
+	cw.code = code
+	cw.header = cw.ResponseWriter.Header().Clone()
+	cw.ResponseWriter.WriteHeader(code)
+}
+
+// Write records the body and forwards it to the client.
+func (cw *captureWriter) Write(p []byte) (int, error) {
+	if !cw.wroteHeader {
+		cw.WriteHeader(http.StatusOK)
+	}
+
+	cw.body.Write(p)
+	return cw.ResponseWriter.Write(p)
+}
+
+// Flush sends buffered data to the client when the underlying writer supports it.
+// The status code is recorded first, since flushing commits it.
+func (cw *captureWriter) Flush() {
+	if !cw.wroteHeader {
+		cw.WriteHeader(http.StatusOK)
+	}
+
+	if flusher, ok := cw.ResponseWriter.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
+
+// Unwrap returns the underlying writer for http.ResponseController.
+func (cw *captureWriter) Unwrap() http.ResponseWriter {
+	return cw.ResponseWriter
+}
+
+// MemoryIdempotencyStore is an in-process IdempotencyStore backed by a map.
+// Expired records are evicted lazily; it suits single-instance deployments and tests.
+type MemoryIdempotencyStore struct {
+	mu        sync.Mutex
+	records   map[string]memoryIdempotencyEntry
+	lastSweep time.Time
+	now       func() time.Time
+}
+
+// memoryIdempotencyEntry is a stored record with its expiry time.
+type memoryIdempotencyEntry struct {
+	record    IdempotencyRecord
+	expiresAt time.Time
+}
+
+// NewMemoryIdempotencyStore creates a new empty in-memory idempotency store.
+func NewMemoryIdempotencyStore() *MemoryIdempotencyStore {
+	return &MemoryIdempotencyStore{
+		records: make(map[string]memoryIdempotencyEntry),
+		now:     time.Now,
+	}
+}
+
+// Reserve claims key unless an unexpired record already exists for it.
+func (s *MemoryIdempotencyStore) Reserve(ctx context.Context, key string, record *IdempotencyRecord, ttl time.Duration) (*IdempotencyRecord, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	s.sweep(now)
+
+	if entry, exists := s.records[key]; exists && now.Before(entry.expiresAt) {
+		existing := entry.record
+		return &existing, false, nil
+	}
+
+	s.records[key] = memoryIdempotencyEntry{record: *record, expiresAt: now.Add(ttl)}
+	return nil, true, nil
+}
+
+// Save stores the completed record for key.
+func (s *MemoryIdempotencyStore) Save(ctx context.Context, key string, record *IdempotencyRecord, ttl time.Duration) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.records[key] = memoryIdempotencyEntry{record: *record, expiresAt: s.now().Add(ttl)}
+	return nil
+}
+
+// Delete forgets key.
+func (s *MemoryIdempotencyStore) Delete(ctx context.Context, key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.records, key)
+	return nil
+}
+
+// sweep evicts expired records at most once per minute. The caller must hold the lock.
+func (s *MemoryIdempotencyStore) sweep(now time.Time) {
+	if now.Sub(s.lastSweep) < time.Minute {
+		return
+	}
+
+	s.lastSweep = now
+	for key, entry := range s.records {
+		if !now.Before(entry.expiresAt) {
+			delete(s.records, key)
+		}
+	}
+}
@@ -0,0 +1,294 @@
+package gores
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+// newIdempotencyTestHandler returns a handler that counts invocations and responds with code
+func newIdempotencyTestHandler(calls *int, code int) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		*calls++
+		NewRenderer().Render(w, r, NewResponseVM[*someStruct]().SetCode(code).SetData(&someStruct{SomeField: "charged"}))
+	})
+}
+
+func TestIdempotency_Handler(t *testing.T) {
+	testCases := []struct {
+		Name            string
+		FirstBody       string
+		SecondBody      string
+		Key             string
+		Method          string
+		HandlerCode     int
+		ExpectedCalls   int
+		ExpectedCode    int
+		ExpectedReplay  bool
+		ExpectedMessage string
+	}{
+		{Name: "Replay", FirstBody: `{"amount":1}`, SecondBody: `{"amount":1}`, Key: "k1", Method: http.MethodPost, HandlerCode: http.StatusCreated, ExpectedCalls: 1, ExpectedCode: http.StatusCreated, ExpectedReplay: true},
+		{Name: "DifferentPayload", FirstBody: `{"amount":1}`, SecondBody: `{"amount":2}`, Key: "k1", Method: http.MethodPost, HandlerCode: http.StatusCreated, ExpectedCalls: 1, ExpectedCode: http.StatusUnprocessableEntity, ExpectedMessage: "different request payload"},
+		{Name: "ServerErrorNotStored", FirstBody: `{}`, SecondBody: `{}`, Key: "k1", Method: http.MethodPost, HandlerCode: http.StatusInternalServerError, ExpectedCalls: 2, ExpectedCode: http.StatusInternalServerError},
+		{Name: "NoKey", FirstBody: `{}`, SecondBody: `{}`, Method: http.MethodPost, HandlerCode: http.StatusCreated, ExpectedCalls: 2, ExpectedCode: http.StatusCreated},
+		{Name: "SafeMethod", Key: "k1", Method: http.MethodGet, HandlerCode: http.StatusOK, ExpectedCalls: 2, ExpectedCode: http.StatusOK},
+	}
+
+	for i := range testCases {
+		t.Run(testCases[i].Name, func(t *testing.T) {
+			calls := 0
+			handler := NewIdempotency(NewMemoryIdempotencyStore()).
+				Handler(newIdempotencyTestHandler(&calls, testCases[i].HandlerCode))
+
+			var second *httptest.ResponseRecorder
+			for _, body := range []string{testCases[i].FirstBody, testCases[i].SecondBody} {
+				r := httptest.NewRequest(testCases[i].Method, "/payments", strings.NewReader(body))
+				if testCases[i].Key != "" {
+					r.Header.Set("Idempotency-Key", testCases[i].Key)
+				}
+				second = httptest.NewRecorder()
+				handler.ServeHTTP(second, r)
+			}
+
+			if calls != testCases[i].ExpectedCalls {
+				t.Errorf("expected calls is %d, got %d", testCases[i].ExpectedCalls, calls)
+			}
+
+			if second.Code != testCases[i].ExpectedCode {
+				t.Errorf("expected code is %d, got %d", testCases[i].ExpectedCode, second.Code)
+			}
+
+			if replayed := second.Header().Get("Idempotent-Replayed") == "true"; replayed != testCases[i].ExpectedReplay {
+				t.Errorf("expected replayed is %t, got %t", testCases[i].ExpectedReplay, replayed)
+			}
+
+			if testCases[i].ExpectedReplay && second.Body.String() != `{"code":201,"data":{"SomeField":"charged"}}` {
+				t.Errorf("unexpected replayed body %s", second.Body.String())
+			}
+
+			if !strings.Contains(second.Body.String(), testCases[i].ExpectedMessage) {
+				t.Errorf("expected body to contain %q, got %s", testCases[i].ExpectedMessage, second.Body.String())
+			}
+		})
+	}
+}
+
+// TestIdempotency_InFlight tests that a concurrent duplicate receives 409 while the first request runs
+func TestIdempotency_InFlight(t *testing.T) {
+	m := NewIdempotency(NewMemoryIdempotencyStore())
+
+	var duplicate *httptest.ResponseRecorder
+	handler := m.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// Issue the duplicate while the original is still being handled
+		dr := httptest.NewRequest(http.MethodPost, "/payments", strings.NewReader(`{}`))
+		dr.Header.Set("Idempotency-Key", "k1")
+		duplicate = httptest.NewRecorder()
+		m.Handler(http.NotFoundHandler()).ServeHTTP(duplicate, dr)
+
+		w.WriteHeader(http.StatusCreated)
+	}))
+
+	r := httptest.NewRequest(http.MethodPost, "/payments", strings.NewReader(`{}`))
+	r.Header.Set("Idempotency-Key", "k1")
+	handler.ServeHTTP(httptest.NewRecorder(), r)
+
+	if duplicate.Code != http.StatusConflict {
+		t.Errorf("expected code is %d, got %d", http.StatusConflict, duplicate.Code)
+	}
+}
+
+func TestMemoryIdempotencyStore_Expiry(t *testing.T) {
+	now := time.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC)
+	store := NewMemoryIdempotencyStore()
+	store.now = func() time.Time { return now }
+	ctx := context.Background()
+
+	if _, reserved, _ := store.Reserve(ctx, "k1", &IdempotencyRecord{}, time.Hour); !reserved {
+		t.Fatal("expected first reserve to succeed")
+	}
+
+	if _, reserved, _ := store.Reserve(ctx, "k1", &IdempotencyRecord{}, time.Hour); reserved {
+		t.Fatal("expected second reserve to fail")
+	}
+
+	now = now.Add(2 * time.Hour)
+	if _, reserved, _ := store.Reserve(ctx, "k1", &IdempotencyRecord{}, time.Hour); !reserved {
+		t.Error("expected reserve after expiry to succeed")
+	}
+}
+
+// failingSaveStore is a MemoryIdempotencyStore whose Save fails, recording the state of its context
+type failingSaveStore struct {
+	*MemoryIdempotencyStore
+	saveErr    error
+	saveCtxErr error
+}
+
+// Save records the context error and fails with saveErr when set
+func (s *failingSaveStore) Save(ctx context.Context, key string, record *IdempotencyRecord, ttl time.Duration) error {
+	s.saveCtxErr = ctx.Err()
+	if s.saveErr != nil {
+		return s.saveErr
+	}
+
+	return s.MemoryIdempotencyStore.Save(ctx, key, record, ttl)
+}
+
+// TestIdempotency_Storage tests implicit responses, save failures and cancelled requests
+func TestIdempotency_Storage(t *testing.T) {
+	testCases := []struct {
+		Name           string
+		SaveErr        error
+		CancelRequest  bool
+		ExpectedCalls  int
+		ExpectedReplay bool
+	}{
+		{Name: "ImplicitOK", ExpectedCalls: 1, ExpectedReplay: true},
+		{Name: "SaveFailureReleasesKey", SaveErr: context.DeadlineExceeded, ExpectedCalls: 2},
+		{Name: "CancelledRequestStillSaved", CancelRequest: true, ExpectedCalls: 1, ExpectedReplay: true},
+	}
+
+	for i := range testCases {
+		t.Run(testCases[i].Name, func(t *testing.T) {
+			store := &failingSaveStore{MemoryIdempotencyStore: NewMemoryIdempotencyStore(), saveErr: testCases[i].SaveErr}
+
+			calls := 0
+			handler := NewIdempotency(store).Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				// Write nothing, leaving net/http to send an implicit 200
+				calls++
+			}))
+
+			var second *httptest.ResponseRecorder
+			for j := 0; j < 2; j++ {
+				ctx, cancel := context.WithCancel(context.Background())
+				if testCases[i].CancelRequest {
+					cancel()
+				}
+
+				r := httptest.NewRequest(http.MethodPost, "/payments", strings.NewReader(`{}`)).WithContext(ctx)
+				r.Header.Set("Idempotency-Key", "k1")
+				second = httptest.NewRecorder()
+				handler.ServeHTTP(second, r)
+				cancel()
+			}
+
+			if store.saveCtxErr != nil {
+				t.Errorf("expected save context to be alive, got %v", store.saveCtxErr)
+			}
+
+			if calls != testCases[i].ExpectedCalls {
+				t.Errorf("expected calls is %d, got %d", testCases[i].ExpectedCalls, calls)
+			}
+
+			if second.Code != http.StatusOK {
+				t.Errorf("expected code is %d, got %d", http.StatusOK, second.Code)
+			}
+
+			if replayed := second.Header().Get("Idempotent-Replayed") == "true"; replayed != testCases[i].ExpectedReplay {
+				t.Errorf("expected replayed is %t, got %t", testCases[i].ExpectedReplay, replayed)
+			}
+		})
+	}
+}
+
+// failingReserveStore is a MemoryIdempotencyStore whose Reserve always fails
+type failingReserveStore struct {
+	*MemoryIdempotencyStore
+}
+
+// Reserve fails with an error carrying internal details
+func (s *failingReserveStore) Reserve(ctx context.Context, key string, record *IdempotencyRecord, ttl time.Duration) (*IdempotencyRecord, bool, error) {
+	return nil, false, errors.New("dial tcp 10.0.0.7:6379: connection refused")
+}
+
+// TestIdempotency_StoreError tests that store errors are reported but not exposed to clients
+func TestIdempotency_StoreError(t *testing.T) {
+	var reported error
+	handler := NewIdempotency(&failingReserveStore{MemoryIdempotencyStore: NewMemoryIdempotencyStore()}).
+		SetReportFunc(func(r *http.Request, err error) { reported = err }).
+		Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			t.Error("expected the handler not to be called")
+		}))
+
+	r := httptest.NewRequest(http.MethodPost, "/payments", strings.NewReader(`{}`))
+	r.Header.Set("Idempotency-Key", "k1")
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, r)
+
+	if w.Code != http.StatusServiceUnavailable {
+		t.Errorf("expected code is %d, got %d", http.StatusServiceUnavailable, w.Code)
+	}
+
+	if strings.Contains(w.Body.String(), "10.0.0.7") {
+		t.Errorf("expected store details to be hidden, got %s", w.Body.String())
+	}
+
+	if reported == nil || !strings.Contains(reported.Error(), "10.0.0.7") {
+		t.Errorf("expected the store error to be reported, got %v", reported)
+	}
+}
+
+// TestIdempotency_MaxBodySize tests that oversized bodies are rejected before fingerprinting completes
+func TestIdempotency_MaxBodySize(t *testing.T) {
+	testCases := []struct {
+		Name          string
+		MaxBodySize   int64
+		Body          string
+		ExpectedCalls int
+		ExpectedCode  int
+	}{
+		{Name: "WithinLimit", MaxBodySize: 16, Body: `{"amount":1}`, ExpectedCalls: 1, ExpectedCode: http.StatusCreated},
+		{Name: "TooLarge", MaxBodySize: 4, Body: `{"amount":1}`, ExpectedCode: http.StatusRequestEntityTooLarge},
+		{Name: "Unlimited", Body: strings.Repeat(" ", 2<<20) + `{}`, ExpectedCalls: 1, ExpectedCode: http.StatusCreated},
+	}
+
+	for i := range testCases {
+		t.Run(testCases[i].Name, func(t *testing.T) {
+			calls := 0
+			handler := NewIdempotency(NewMemoryIdempotencyStore()).
+				SetMaxBodySize(testCases[i].MaxBodySize).
+				Handler(newIdempotencyTestHandler(&calls, http.StatusCreated))
+
+			r := httptest.NewRequest(http.MethodPost, "/payments", strings.NewReader(testCases[i].Body))
+			r.Header.Set("Idempotency-Key", "k1")
+			w := httptest.NewRecorder()
+			handler.ServeHTTP(w, r)
+
+			if calls != testCases[i].ExpectedCalls {
+				t.Errorf("expected calls is %d, got %d", testCases[i].ExpectedCalls, calls)
+			}
+
+			if w.Code != testCases[i].ExpectedCode {
+				t.Errorf("expected code is %d, got %d", testCases[i].ExpectedCode, w.Code)
+			}
+		})
+	}
+}
+
+// TestIdempotency_Flush tests that streaming handlers can flush through the middleware
+func TestIdempotency_Flush(t *testing.T) {
+	store := NewMemoryIdempotencyStore()
+	handler := NewIdempotency(store).Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("chunk"))
+		if err := http.NewResponseController(w).Flush(); err != nil {
+			t.Errorf("unexpected error: %v", err)
+		}
+	}))
+
+	r := httptest.NewRequest(http.MethodPost, "/payments", strings.NewReader(`{}`))
+	r.Header.Set("Idempotency-Key", "k1")
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, r)
+
+	if !w.Flushed {
+		t.Error("expected the response to be flushed")
+	}
+
+	if record, reserved, _ := store.Reserve(context.Background(), "k1", &IdempotencyRecord{}, time.Hour); reserved || string(record.Body) != "chunk" {
+		t.Errorf("expected the flushed response to be stored, got %+v", record)
+	}
+}
@@ -0,0 +1,137 @@
+package gores
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"net/http"
+	"sync"
+
+	"github.com/fikri240794/gocerr"
+)
+
+// JobStore persists asynchronous job state between the request that starts
+// a job and the requests that poll it. Implementations must be safe for concurrent use.
+// Get returns a gocerr.Error with HTTP 404 when the job does not exist so the error
+// can be passed straight to ResponseVM.SetErrorFromError.
+type JobStore interface {
+	Create(ctx context.Context, job *JobVM) error
+	Get(ctx context.Context, id string) (*JobVM, error)
+	Update(ctx context.Context, job *JobVM) error
+	Delete(ctx context.Context, id string) error
+}
+
+// MemoryJobStore is an in-process JobStore backed by a map.
+// It is intended for single-instance deployments and tests; jobs are lost on restart.
+// Jobs are copied in and out, including their Error, but Result and the current state of
+// a conflict are interface values shared with the caller and must not be mutated.
+type MemoryJobStore struct {
+	mu   sync.RWMutex
+	jobs map[string]JobVM
+}
+
+// NewMemoryJobStore creates a new empty in-memory job store.
+func NewMemoryJobStore() *MemoryJobStore {
+	return &MemoryJobStore{
+		jobs: make(map[string]JobVM),
+	}
+}
+
+// Create stores a new job, generating an identifier when the job has none.
+// It returns a 409 error if a job with the same identifier already exists.
+func (s *MemoryJobStore) Create(ctx context.Context, job *JobVM) error {
+	if job.ID == "" {
+		id, err := newRandomID()
+		if err != nil {
+			return err
+		}
+		job.ID = id
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, exists := s.jobs[job.ID]; exists {
+		return gocerr.New(http.StatusConflict, "job "+job.ID+" already exists")
+	}
+
+	// Store a copy so callers cannot mutate stored state without Update
+	s.jobs[job.ID] = copyJob(job)
+	return nil
+}
+
+// Get returns a copy of the job with the given identifier.
+func (s *MemoryJobStore) Get(ctx context.Context, id string) (*JobVM, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	job, exists := s.jobs[id]
+	if !exists {
+		return nil, gocerr.New(http.StatusNotFound, "job "+id+" not found")
+	}
+
+	job = copyJob(&job)
+	return &job, nil
+}
+
+// Update replaces the stored state of an existing job.
+func (s *MemoryJobStore) Update(ctx context.Context, job *JobVM) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, exists := s.jobs[job.ID]; !exists {
+		return gocerr.New(http.StatusNotFound, "job "+job.ID+" not found")
+	}
+
+	s.jobs[job.ID] = copyJob(job)
+	return nil
+}
+
+// Delete removes a job. Deleting an unknown job is not an error.
+func (s *MemoryJobStore) Delete(ctx context.Context, id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.jobs, id)
+	return nil
+}
+
+// copyJob copies job together with its error details, which callers commonly modify.
+func copyJob(job *JobVM) JobVM {
+	copied := *job
+	if job.Error == nil {
+		return copied
+	}
+
+	errVM := *job.Error
+	if job.Error.ErrorFields != nil {
+		errVM.ErrorFields = make([]*ResponseErrorFieldVM, len(job.Error.ErrorFields))
+		for i, field := range job.Error.ErrorFields {
+			if field == nil {
+				continue
+			}
+
+			fieldCopy := *field
+			errVM.ErrorFields[i] = &fieldCopy
+		}
+	}
+
+	if job.Error.Conflict != nil {
+		conflict := *job.Error.Conflict
+		conflict.ConflictingFields = append([]string(nil), conflict.ConflictingFields...)
+		errVM.Conflict = &conflict
+	}
+
+	copied.Error = &errVM
+	return copied
+}
+
+// newRandomID generates a random 128-bit hexadecimal identifier.
+func newRandomID() (string, error) {
+	var buf [16]byte
+	if _, err := rand.Read(buf[:]); err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(buf[:]), nil
+}
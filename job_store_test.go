@@ -0,0 +1,105 @@
+package gores
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/fikri240794/gocerr"
+)
+
+func TestMemoryJobStore(t *testing.T) {
+	var store JobStore = NewMemoryJobStore()
+	ctx := context.Background()
+
+	job := NewJobVM("")
+	if err := store.Create(ctx, job); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if job.ID == "" {
+		t.Fatal("expected generated job id")
+	}
+
+	if err := store.Create(ctx, job); gocerr.GetErrorCode(err) != http.StatusConflict {
+		t.Errorf("expected code is %d, got %d", http.StatusConflict, gocerr.GetErrorCode(err))
+	}
+
+	// Mutating the caller copy must not affect stored state
+	job.SetProgress(50)
+	stored, err := store.Get(ctx, job.ID)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if stored.Progress != 0 {
+		t.Errorf("expected stored progress is 0, got %d", stored.Progress)
+	}
+
+	if err := store.Update(ctx, job); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	stored, _ = store.Get(ctx, job.ID)
+	if stored.Progress != 50 {
+		t.Errorf("expected stored progress is 50, got %d", stored.Progress)
+	}
+
+	if err := store.Delete(ctx, job.ID); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if _, err := store.Get(ctx, job.ID); gocerr.GetErrorCode(err) != http.StatusNotFound {
+		t.Errorf("expected code is %d, got %d", http.StatusNotFound, gocerr.GetErrorCode(err))
+	}
+
+	if err := store.Update(ctx, job); gocerr.GetErrorCode(err) != http.StatusNotFound {
+		t.Errorf("expected code is %d, got %d", http.StatusNotFound, gocerr.GetErrorCode(err))
+	}
+}
+
+// TestMemoryJobStore_CopiesError tests that error details are not shared with callers
+func TestMemoryJobStore_CopiesError(t *testing.T) {
+	store := NewMemoryJobStore()
+	ctx := context.Background()
+
+	job := NewJobVM("job-1").SetErrorFromError(gocerr.New(
+		http.StatusUnprocessableEntity,
+		"import failed",
+		gocerr.NewErrorField("file", "is empty"),
+	))
+	if err := store.Create(ctx, job); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	job.Error.Message = "changed"
+	job.Error.ErrorFields[0].Message = "changed"
+
+	stored, _ := store.Get(ctx, "job-1")
+	if stored.Error.Message != "import failed" || stored.Error.ErrorFields[0].Message != "is empty" {
+		t.Fatalf("expected stored error to be unchanged, got %+v", stored.Error)
+	}
+
+	stored.Error.ErrorFields[0].Message = "changed"
+	if again, _ := store.Get(ctx, "job-1"); again.Error.ErrorFields[0].Message != "is empty" {
+		t.Errorf("expected stored error to be unchanged by Get callers, got %s", again.Error.ErrorFields[0].Message)
+	}
+}
+
+// TestMemoryJobStore_NilErrorField tests that nil error fields are copied without panicking
+func TestMemoryJobStore_NilErrorField(t *testing.T) {
+	store := NewMemoryJobStore()
+	ctx := context.Background()
+
+	job := NewJobVM("job-1").SetErrorFromError(errors.New("import failed"))
+	job.Error.AddErrorFields(nil, NewResponseErrorFieldVM("file", "is empty"))
+	if err := store.Create(ctx, job); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	stored, _ := store.Get(ctx, "job-1")
+	if len(stored.Error.ErrorFields) != 2 || stored.Error.ErrorFields[0] != nil || stored.Error.ErrorFields[1].Field != "file" {
+		t.Errorf("expected error fields to be copied as is, got %+v", stored.Error.ErrorFields)
+	}
+}
@@ -0,0 +1,335 @@
+package gores
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// MetaVM carries response metadata that is not part of the data payload,
+// such as server time, API version, request ID and processing duration.
+// Values holds arbitrary additional entries, written inline next to the typed ones.
+type MetaVM struct {
+	Timestamp  *time.Time             `json:"timestamp,omitempty"`   // Server time when the response was rendered
+	APIVersion string                 `json:"api_version,omitempty"` // Version of the API that produced the response
+	RequestID  string                 `json:"request_id,omitempty"`  // Identifier correlating the request in logs
+	DurationMS *float64               `json:"duration_ms,omitempty"` // Processing duration in milliseconds
+	Values     map[string]interface{} `json:"-"`                     // Additional metadata written inline
+}
+
+// NewMetaVM creates a new empty metadata block.
+// The Values map is pre-allocated so entries can be added immediately.
+func NewMetaVM() *MetaVM {
+	return &MetaVM{
+		Values: make(map[string]interface{}),
+	}
+}
+
+// SetTimestamp sets the server time of the response.
+// This method follows the fluent API pattern for method chaining.
+func (vm *MetaVM) SetTimestamp(timestamp time.Time) *MetaVM {
+	timestamp = timestamp.UTC()
+	vm.Timestamp = &timestamp
+	return vm
+}
+
+// SetAPIVersion sets the API version.
+// This method follows the fluent API pattern for method chaining.
+func (vm *MetaVM) SetAPIVersion(apiVersion string) *MetaVM {
+	vm.APIVersion = apiVersion
+	return vm
+}
+
+// SetRequestID sets the request identifier.
+// This method follows the fluent API pattern for method chaining.
+func (vm *MetaVM) SetRequestID(requestID string) *MetaVM {
+	vm.RequestID = requestID
+	return vm
+}
+
+// SetDuration sets the processing duration, written in fractional milliseconds.
+// This method follows the fluent API pattern for method chaining.
+func (vm *MetaVM) SetDuration(duration time.Duration) *MetaVM {
+	ms := float64(duration.Microseconds()) / 1000
+	vm.DurationMS = &ms
+	return vm
+}
+
+// SetValue sets an arbitrary metadata entry.
+// Entries named like a typed field are ignored when encoding.
+func (vm *MetaVM) SetValue(key string, value interface{}) *MetaVM {
+	if vm.Values == nil {
+		vm.Values = make(map[string]interface{})
+	}
+
+	vm.Values[key] = value
+	return vm
+}
+
+// metaAlias has the fields of MetaVM without its JSON methods.
+type metaAlias MetaVM
+
+// MarshalJSON writes typed fields first followed by Values in key order.
+func (vm MetaVM) MarshalJSON() ([]byte, error) {
+	typed, err := json.Marshal(metaAlias(vm))
+	if err != nil {
+		return nil, err
+	}
+
+	members, err := decodeJSONObject(typed)
+	if err != nil {
+		return nil, err
+	}
+
+	reserved := make(map[string]bool, len(members))
+	for _, member := range members {
+		reserved[member.key] = true
+	}
+
+	keys := make([]string, 0, len(vm.Values))
+	for key := range vm.Values {
+		if !reserved[key] {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		value, err := json.Marshal(vm.Values[key])
+		if err != nil {
+			return nil, err
+		}
+
+		members = append(members, jsonMember{key: key, value: value})
+	}
+
+	return members.raw(), nil
+}
+
+// UnmarshalJSON reads typed fields and collects every other entry into Values.
+func (vm *MetaVM) UnmarshalJSON(data []byte) error {
+	var typed metaAlias
+	if err := json.Unmarshal(data, &typed); err != nil {
+		return err
+	}
+
+	var values map[string]interface{}
+	if err := json.Unmarshal(data, &values); err != nil {
+		return err
+	}
+
+	for _, key := range []string{"timestamp", "api_version", "request_id", "duration_ms"} {
+		delete(values, key)
+	}
+
+	*vm = MetaVM(typed)
+	vm.Values = values
+	return nil
+}
+
+// clone returns a copy of vm that does not share its Values map.
+func (vm *MetaVM) clone() *MetaVM {
+	copied := *vm
+	copied.Values = make(map[string]interface{}, len(vm.Values))
+	for key, value := range vm.Values {
+		copied.Values[key] = value
+	}
+
+	return &copied
+}
+
+// merge fills fields that are unset in vm from other; explicit values in vm win.
+func (vm *MetaVM) merge(other *MetaVM) *MetaVM {
+	if vm.Timestamp == nil {
+		vm.Timestamp = other.Timestamp
+	}
+
+	if vm.APIVersion == "" {
+		vm.APIVersion = other.APIVersion
+	}
+
+	if vm.RequestID == "" {
+		vm.RequestID = other.RequestID
+	}
+
+	if vm.DurationMS == nil {
+		vm.DurationMS = other.DurationMS
+	}
+
+	for key, value := range other.Values {
+		if _, exists := vm.Values[key]; !exists {
+			vm.SetValue(key, value)
+		}
+	}
+
+	return vm
+}
+
+// MetaHook populates response metadata at render time from the request context.
+type MetaHook func(ctx context.Context, meta *MetaVM)
+
+// metaContextKey is the context key under which the metaCollector is stored.
+type metaContextKey struct{}
+
+// serverTiming is a single Server-Timing metric.
+type serverTiming struct {
+	name     string
+	duration time.Duration
+}
+
+// metaCollector accumulates metadata for a request between middleware and Renderer.
+type metaCollector struct {
+	mu         sync.Mutex
+	start      time.Time
+	apiVersion string
+	requestID  string
+	values     map[string]interface{}
+	timings    []serverTiming
+	hooks      []MetaHook
+}
+
+// metaCollectorFromContext returns the collector installed by the Meta middleware.
+func metaCollectorFromContext(ctx context.Context) *metaCollector {
+	collector, _ := ctx.Value(metaContextKey{}).(*metaCollector)
+	return collector
+}
+
+// SetMetaValue records an arbitrary metadata entry for the current request.
+// It is a no-op when the request is not wrapped by the Meta middleware.
+func SetMetaValue(ctx context.Context, key string, value interface{}) {
+	collector := metaCollectorFromContext(ctx)
+	if collector == nil {
+		return
+	}
+
+	collector.mu.Lock()
+	defer collector.mu.Unlock()
+
+	collector.values[key] = value
+}
+
+// AddServerTiming records a named duration for the Server-Timing header,
+// e.g. AddServerTiming(ctx, "db", elapsed). Names should be valid HTTP tokens.
+// It is a no-op when the request is not wrapped by the Meta middleware.
+func AddServerTiming(ctx context.Context, name string, duration time.Duration) {
+	collector := metaCollectorFromContext(ctx)
+	if collector == nil {
+		return
+	}
+
+	collector.mu.Lock()
+	defer collector.mu.Unlock()
+
+	collector.timings = append(collector.timings, serverTiming{name: name, duration: duration})
+}
+
+// RequestIDFromContext returns the request ID assigned by the Meta middleware.
+func RequestIDFromContext(ctx context.Context) string {
+	collector := metaCollectorFromContext(ctx)
+	if collector == nil {
+		return ""
+	}
+
+	return collector.requestID
+}
+
+// build snapshots the collected metadata, runs the hooks and returns the
+// metadata block together with the Server-Timing header value.
+func (c *metaCollector) build(ctx context.Context, now time.Time) (*MetaVM, string) {
+	c.mu.Lock()
+	meta := NewMetaVM().
+		SetTimestamp(now).
+		SetAPIVersion(c.apiVersion).
+		SetRequestID(c.requestID).
+		SetDuration(now.Sub(c.start))
+
+	for key, value := range c.values {
+		meta.SetValue(key, value)
+	}
+
+	timings := make([]string, 0, len(c.timings)+1)
+	for _, timing := range c.timings {
+		timings = append(timings, formatServerTiming(timing.name, timing.duration))
+	}
+	timings = append(timings, formatServerTiming("total", now.Sub(c.start)))
+	hooks := c.hooks
+	c.mu.Unlock()
+
+	for _, hook := range hooks {
+		hook(ctx, meta)
+	}
+
+	return meta, strings.Join(timings, ", ")
+}
+
+// formatServerTiming formats a Server-Timing metric with a millisecond duration.
+func formatServerTiming(name string, duration time.Duration) string {
+	return name + ";dur=" + strconv.FormatFloat(float64(duration.Microseconds())/1000, 'f', -1, 64)
+}
+
+// Meta is a middleware that enables the meta block on every response rendered
+// for the request. It stamps a request ID, API version, server time and processing
+// duration, runs registered hooks, and emits a Server-Timing header with the
+// durations recorded via AddServerTiming.
+type Meta struct {
+	apiVersion      string
+	requestIDHeader string
+	hooks           []MetaHook
+	now             func() time.Time
+}
+
+// NewMeta creates a new Meta middleware reading and echoing request IDs in X-Request-ID.
+func NewMeta() *Meta {
+	return &Meta{
+		requestIDHeader: "X-Request-ID",
+		now:             time.Now,
+	}
+}
+
+// SetAPIVersion sets the API version written to every meta block.
+// This method uses method chaining pattern for fluent API design.
+func (m *Meta) SetAPIVersion(apiVersion string) *Meta {
+	m.apiVersion = apiVersion
+	return m
+}
+
+// SetRequestIDHeader sets the header used to read and echo request IDs.
+// Requests without the header get a generated ID.
+func (m *Meta) SetRequestIDHeader(header string) *Meta {
+	m.requestIDHeader = header
+	return m
+}
+
+// AddHooks registers hooks that populate metadata at render time.
+// This method uses method chaining pattern for fluent API design.
+func (m *Meta) AddHooks(hooks ...MetaHook) *Meta {
+	m.hooks = append(m.hooks, hooks...)
+	return m
+}
+
+// Handler wraps next so that responses rendered by a Renderer carry metadata.
+func (m *Meta) Handler(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requestID := r.Header.Get(m.requestIDHeader)
+		if requestID == "" {
+			requestID, _ = newRandomID()
+		}
+
+		w.Header().Set(m.requestIDHeader, requestID)
+
+		collector := &metaCollector{
+			start:      m.now(),
+			apiVersion: m.apiVersion,
+			requestID:  requestID,
+			values:     make(map[string]interface{}),
+			hooks:      m.hooks,
+		}
+
+		next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), metaContextKey{}, collector)))
+	})
+}
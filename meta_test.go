@@ -0,0 +1,214 @@
+package gores
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestMetaVM_JSON(t *testing.T) {
+	meta := NewMetaVM().
+		SetTimestamp(time.Date(2024, time.January, 2, 3, 4, 5, 0, time.UTC)).
+		SetAPIVersion("v2").
+		SetRequestID("req-1").
+		SetDuration(1500*time.Microsecond).
+		SetValue("region", "eu-west-1").
+		SetValue("api_version", "ignored")
+
+	body, err := json.Marshal(meta)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expected := `{"timestamp":"2024-01-02T03:04:05Z","api_version":"v2","request_id":"req-1","duration_ms":1.5,"region":"eu-west-1"}`
+	if string(body) != expected {
+		t.Errorf("expected body is %s, got %s", expected, string(body))
+	}
+
+	var decoded MetaVM
+	if err := json.Unmarshal(body, &decoded); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if decoded.APIVersion != "v2" || decoded.RequestID != "req-1" || *decoded.DurationMS != 1.5 {
+		t.Errorf("unexpected typed fields after round-trip: %+v", decoded)
+	}
+
+	if len(decoded.Values) != 1 || decoded.Values["region"] != "eu-west-1" {
+		t.Errorf("expected only region in values, got %v", decoded.Values)
+	}
+}
+
+func TestMeta_Handler(t *testing.T) {
+	testCases := []struct {
+		Name              string
+		RequestID         string
+		Response          *ResponseVM[*someStruct]
+		ExpectedRequestID string
+		ExpectedVersion   string
+	}{
+		{
+			Name:              "CollectedMeta",
+			RequestID:         "req-1",
+			Response:          NewResponseVM[*someStruct]().SetCode(http.StatusOK),
+			ExpectedRequestID: "req-1",
+			ExpectedVersion:   "v2",
+		},
+		{
+			Name:              "ExplicitMetaWins",
+			RequestID:         "req-1",
+			Response:          NewResponseVM[*someStruct]().SetCode(http.StatusOK).SetMeta(NewMetaVM().SetAPIVersion("v3")),
+			ExpectedRequestID: "req-1",
+			ExpectedVersion:   "v3",
+		},
+	}
+
+	for i := range testCases {
+		t.Run(testCases[i].Name, func(t *testing.T) {
+			hook := func(ctx context.Context, meta *MetaVM) {
+				meta.SetValue("hooked", RequestIDFromContext(ctx))
+			}
+
+			handler := NewMeta().
+				SetAPIVersion("v2").
+				AddHooks(hook).
+				Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+					SetMetaValue(r.Context(), "region", "eu-west-1")
+					AddServerTiming(r.Context(), "db", 12*time.Millisecond)
+					NewRenderer().Render(w, r, testCases[i].Response)
+				}))
+
+			r := httptest.NewRequest(http.MethodGet, "/", nil)
+			r.Header.Set("X-Request-ID", testCases[i].RequestID)
+			w := httptest.NewRecorder()
+			handler.ServeHTTP(w, r)
+
+			var decoded ResponseVM[*someStruct]
+			if err := json.Unmarshal(w.Body.Bytes(), &decoded); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if decoded.Meta == nil {
+				t.Fatal("expected meta block")
+			}
+
+			if decoded.Meta.RequestID != testCases[i].ExpectedRequestID {
+				t.Errorf("expected request id is %s, got %s", testCases[i].ExpectedRequestID, decoded.Meta.RequestID)
+			}
+
+			if decoded.Meta.APIVersion != testCases[i].ExpectedVersion {
+				t.Errorf("expected api version is %s, got %s", testCases[i].ExpectedVersion, decoded.Meta.APIVersion)
+			}
+
+			if decoded.Meta.Timestamp == nil || decoded.Meta.DurationMS == nil {
+				t.Error("expected timestamp and duration")
+			}
+
+			if decoded.Meta.Values["region"] != "eu-west-1" || decoded.Meta.Values["hooked"] != testCases[i].ExpectedRequestID {
+				t.Errorf("unexpected values %v", decoded.Meta.Values)
+			}
+
+			if w.Header().Get("X-Request-ID") != testCases[i].ExpectedRequestID {
+				t.Errorf("expected echoed request id is %s, got %s", testCases[i].ExpectedRequestID, w.Header().Get("X-Request-ID"))
+			}
+
+			if timing := w.Header().Get("Server-Timing"); !strings.HasPrefix(timing, "db;dur=12, total;dur=") {
+				t.Errorf("unexpected server timing %q", timing)
+			}
+		})
+	}
+}
+
+// TestMeta_Handler_GeneratedRequestID tests that a request ID is generated when absent
+func TestMeta_Handler_GeneratedRequestID(t *testing.T) {
+	var requestID string
+	handler := NewMeta().Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requestID = RequestIDFromContext(r.Context())
+	}))
+
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/", nil))
+
+	if requestID == "" || w.Header().Get("X-Request-ID") != requestID {
+		t.Errorf("expected generated request id to be echoed, got %q and %q", requestID, w.Header().Get("X-Request-ID"))
+	}
+}
+
+// TestMeta_WithoutMiddleware tests that context helpers are no-ops without the middleware
+func TestMeta_WithoutMiddleware(t *testing.T) {
+	ctx := context.Background()
+	SetMetaValue(ctx, "key", "value")
+	AddServerTiming(ctx, "db", time.Millisecond)
+
+	if RequestIDFromContext(ctx) != "" {
+		t.Error("expected empty request id")
+	}
+
+	w := httptest.NewRecorder()
+	NewRenderer().Render(w, httptest.NewRequest(http.MethodGet, "/", nil), NewResponseVM[*someStruct]().SetCode(http.StatusOK))
+
+	if w.Body.String() != `{"code":200}` || w.Header().Get("Server-Timing") != "" {
+		t.Errorf("expected no meta, got %s", w.Body.String())
+	}
+}
+
+// TestMeta_Handler_ConditionalRequest tests that collected meta does not change computed ETags
+func TestMeta_Handler_ConditionalRequest(t *testing.T) {
+	renderer := NewRenderer().SetETagMode(ETagStrong)
+	handler := NewMeta().Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		renderer.Render(w, r, NewResponseVM[*someStruct]().SetCode(http.StatusOK).SetData(&someStruct{SomeField: "value"}))
+	}))
+
+	first := httptest.NewRecorder()
+	handler.ServeHTTP(first, httptest.NewRequest(http.MethodGet, "/", nil))
+
+	etag := first.Header().Get("ETag")
+	if etag == "" || !strings.Contains(first.Body.String(), `"request_id"`) {
+		t.Fatalf("expected ETag and meta, got %q and %s", etag, first.Body.String())
+	}
+
+	r := httptest.NewRequest(http.MethodGet, "/", nil)
+	r.Header.Set("If-None-Match", etag)
+	second := httptest.NewRecorder()
+	handler.ServeHTTP(second, r)
+
+	if second.Code != http.StatusNotModified {
+		t.Errorf("expected code is %d, got %d", http.StatusNotModified, second.Code)
+	}
+
+	if second.Header().Get("ETag") != etag {
+		t.Errorf("expected ETag is %s, got %s", etag, second.Header().Get("ETag"))
+	}
+}
+
+// TestMeta_Handler_SharedMeta tests that collected meta is not written into a MetaVM shared across requests
+func TestMeta_Handler_SharedMeta(t *testing.T) {
+	shared := NewMetaVM().SetAPIVersion("v1")
+	handler := NewMeta().Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		NewRenderer().Render(w, r, NewResponseVM[*someStruct]().SetCode(http.StatusOK).SetMeta(shared))
+	}))
+
+	for _, requestID := range []string{"req-1", "req-2"} {
+		r := httptest.NewRequest(http.MethodGet, "/", nil)
+		r.Header.Set("X-Request-ID", requestID)
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, r)
+
+		var decoded ResponseVM[*someStruct]
+		if err := json.Unmarshal(w.Body.Bytes(), &decoded); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if decoded.Meta == nil || decoded.Meta.RequestID != requestID || decoded.Meta.APIVersion != "v1" {
+			t.Errorf("expected request id %s and api version v1, got %+v", requestID, decoded.Meta)
+		}
+	}
+
+	if shared.RequestID != "" || shared.Timestamp != nil || shared.DurationMS != nil {
+		t.Errorf("expected shared meta to be left untouched, got %+v", shared)
+	}
+}
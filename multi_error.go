@@ -0,0 +1,140 @@
+package gores
+
+import (
+	"strings"
+
+	"github.com/fikri240794/gocerr"
+)
+
+// StatusStrategy picks the response status code from the codes of every gocerr.Error
+// found in a multi-error tree, e.g. one built with errors.Join. Codes are passed in
+// depth-first order and there is always at least one.
+type StatusStrategy func(codes []int) int
+
+// StatusMostSevere picks the highest status class among the codes. Within that class it
+// returns the common code, or the generic 400 or 500 when the codes differ, so that joining
+// a 404 and a 409 yields 400 while joining either with a 503 yields 503.
+func StatusMostSevere(codes []int) int {
+	severe := codes[0]
+	for _, code := range codes[1:] {
+		switch {
+		case code/100 > severe/100:
+			severe = code
+		case code/100 == severe/100 && code != severe:
+			severe = code / 100 * 100
+		}
+	}
+
+	return severe
+}
+
+// StatusFirst picks the code of the first gocerr.Error found in the tree.
+// It matches the behavior of gocerr.GetErrorCode.
+func StatusFirst(codes []int) int {
+	return codes[0]
+}
+
+// customError is an error found in a multi-error tree together with its status code.
+// Errors returned as a ResponseErrorVM or ResponseErrorFieldVM carry no status code.
+type customError struct {
+	code     int              // HTTP status code, 0 when unknown
+	errorVM  *ResponseErrorVM // Error details of the custom error
+	response bool             // Whether the error was a ResponseErrorVM
+}
+
+// statusFromCustomErrors applies strategy, or StatusMostSevere when nil, to the codes of
+// customErrors. It returns 0 when no error carries a code.
+func statusFromCustomErrors(customErrors []customError, strategy StatusStrategy) int {
+	codes := make([]int, 0, len(customErrors))
+	for i := range customErrors {
+		if customErrors[i].code != 0 {
+			codes = append(codes, customErrors[i].code)
+		}
+	}
+
+	if len(codes) == 0 {
+		return 0
+	}
+
+	if strategy == nil {
+		strategy = StatusMostSevere
+	}
+
+	return strategy(codes)
+}
+
+// collectCustomErrors walks the error tree depth-first, following both Unwrap() error
+// and Unwrap() []error, and returns every gocerr.Error, ResponseErrorVM and ResponseErrorFieldVM
+// it finds. The chain below any of them is not walked further.
+func collectCustomErrors(err error) []customError {
+	var customErrors []customError
+
+	var walk func(err error)
+	walk = func(err error) {
+		switch e := err.(type) {
+		case nil:
+		case gocerr.Error:
+			customErrors = append(customErrors, customError{code: e.Code, errorVM: NewResponseErrorVM().mapFromCustomError(e)})
+		case *gocerr.Error:
+			if e != nil {
+				customErrors = append(customErrors, customError{code: e.Code, errorVM: NewResponseErrorVM().mapFromCustomError(*e)})
+			}
+		case *ResponseErrorVM:
+			if e != nil {
+				customErrors = append(customErrors, customError{errorVM: e, response: true})
+			}
+		case *ResponseErrorFieldVM:
+			if e != nil {
+				customErrors = append(customErrors, customError{errorVM: NewResponseErrorVM().AddErrorFields(e)})
+			}
+		case interface{ Unwrap() []error }:
+			for _, child := range e.Unwrap() {
+				walk(child)
+			}
+		case interface{ Unwrap() error }:
+			walk(e.Unwrap())
+		}
+	}
+
+	walk(err)
+	return customErrors
+}
+
+// mapFromCustomErrors merges the details of several custom errors into the error response.
+// Distinct messages are joined with "; " and identical field errors are kept once, combining
+// the location, code and rejected value each of them carries.
+func (vm *ResponseErrorVM) mapFromCustomErrors(customErrors []customError) *ResponseErrorVM {
+	messages := make([]string, 0, len(customErrors))
+	seenMessages := make(map[string]bool, len(customErrors))
+
+	for i := range customErrors {
+		if message := customErrors[i].errorVM.Message; message != "" && !seenMessages[message] {
+			seenMessages[message] = true
+			messages = append(messages, message)
+		}
+
+		for _, errorField := range customErrors[i].errorVM.ErrorFields {
+			if errorField == nil || vm.enrichErrorField(errorField) {
+				continue
+			}
+
+			copied := *errorField
+			vm.AddErrorFields(&copied)
+		}
+	}
+
+	vm.Message = strings.Join(messages, "; ")
+	return vm
+}
+
+// enrichErrorField merges errorField into an identical field error already collected,
+// see ResponseErrorFieldVM.enrich. It reports whether such a field error was found.
+func (vm *ResponseErrorVM) enrichErrorField(errorField *ResponseErrorFieldVM) bool {
+	for _, existing := range vm.ErrorFields {
+		if existing != nil && existing.enrich(errorField) {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,101 @@
+package gores
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/fikri240794/gocerr"
+)
+
+func TestStatusMostSevere(t *testing.T) {
+	var testCases = []struct {
+		Name     string
+		Codes    []int
+		Expected int
+	}{
+		{Name: "single code", Codes: []int{http.StatusNotFound}, Expected: http.StatusNotFound},
+		{Name: "same codes", Codes: []int{http.StatusUnprocessableEntity, http.StatusUnprocessableEntity}, Expected: http.StatusUnprocessableEntity},
+		{Name: "different client codes", Codes: []int{http.StatusNotFound, http.StatusConflict}, Expected: http.StatusBadRequest},
+		{Name: "server code wins", Codes: []int{http.StatusBadRequest, http.StatusServiceUnavailable, http.StatusNotFound}, Expected: http.StatusServiceUnavailable},
+		{Name: "different server codes", Codes: []int{http.StatusBadGateway, http.StatusServiceUnavailable}, Expected: http.StatusInternalServerError},
+	}
+
+	for i := range testCases {
+		t.Run(testCases[i].Name, func(t *testing.T) {
+			if actual := StatusMostSevere(testCases[i].Codes); actual != testCases[i].Expected {
+				t.Errorf("expected code is %d, got %d", testCases[i].Expected, actual)
+			}
+		})
+	}
+}
+
+func TestResponseVM_SetErrorFromError_JoinedErrors(t *testing.T) {
+	err := errors.Join(
+		gocerr.New(
+			http.StatusBadRequest,
+			"invalid request",
+			gocerr.NewErrorField("name", "name is required"),
+			gocerr.NewErrorField("email", "email is invalid"),
+		),
+		fmt.Errorf("validating address: %w", gocerr.New(
+			http.StatusUnprocessableEntity,
+			"invalid address",
+			gocerr.NewErrorField("email", "email is invalid"),
+			gocerr.NewErrorField("address.city", "city is required"),
+		)),
+		errors.New("plain error"),
+	)
+
+	vm := NewResponseVM[*someStruct]().SetErrorFromError(err)
+
+	if vm.Code != http.StatusBadRequest {
+		t.Errorf("expected code is %d, got %d", http.StatusBadRequest, vm.Code)
+	}
+
+	expected := NewResponseErrorVM().
+		SetMessage("invalid request; invalid address").
+		AddErrorFields(
+			NewResponseErrorFieldVM("name", "name is required"),
+			NewResponseErrorFieldVM("email", "email is invalid"),
+			NewResponseErrorFieldVM("address.city", "city is required"),
+		)
+	testResponseErrorVMEquality(t, expected, vm.Error)
+}
+
+func TestRenderer_SetStatusStrategy(t *testing.T) {
+	err := errors.Join(
+		gocerr.New(http.StatusNotFound, "not found"),
+		gocerr.New(http.StatusServiceUnavailable, "unavailable"),
+	)
+
+	testCases := []struct {
+		Name         string
+		Renderer     *Renderer
+		ExpectedCode int
+	}{
+		{Name: "Default", Renderer: NewRenderer(), ExpectedCode: http.StatusServiceUnavailable},
+		{Name: "StatusFirst", Renderer: NewRenderer().SetStatusStrategy(StatusFirst), ExpectedCode: http.StatusNotFound},
+		{Name: "Restored", Renderer: NewRenderer().SetStatusStrategy(StatusFirst).SetStatusStrategy(nil), ExpectedCode: http.StatusServiceUnavailable},
+	}
+
+	for i := range testCases {
+		t.Run(testCases[i].Name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			if renderErr := testCases[i].Renderer.RenderError(w, nil, err); renderErr != nil {
+				t.Fatalf("unexpected error: %v", renderErr)
+			}
+
+			if w.Code != testCases[i].ExpectedCode {
+				t.Errorf("expected code is %d, got %d", testCases[i].ExpectedCode, w.Code)
+			}
+		})
+	}
+
+	// Renderers do not affect each other or SetErrorFromError
+	if code := NewResponseVM[*someStruct]().SetErrorFromError(err).Code; code != http.StatusServiceUnavailable {
+		t.Errorf("expected code is %d, got %d", http.StatusServiceUnavailable, code)
+	}
+}
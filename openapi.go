@@ -0,0 +1,462 @@
+package gores
+
+import (
+	"encoding"
+	"encoding/json"
+	"net/http"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Schema is an OpenAPI 3.1 schema object, i.e. a JSON Schema 2020-12 subset.
+// Only the keywords needed to describe gores envelopes are modeled.
+type Schema struct {
+	Ref                  string             `json:"$ref,omitempty"`                 // Reference to a component schema
+	Type                 string             `json:"type,omitempty"`                 // JSON type
+	Format               string             `json:"format,omitempty"`               // Format hint such as date-time
+	Description          string             `json:"description,omitempty"`          // Human-readable description
+	Enum                 []interface{}      `json:"enum,omitempty"`                 // Allowed values
+	Properties           map[string]*Schema `json:"properties,omitempty"`           // Object members
+	Required             []string           `json:"required,omitempty"`             // Members that must be present
+	Items                *Schema            `json:"items,omitempty"`                // Schema of array items
+	AdditionalProperties *Schema            `json:"additionalProperties,omitempty"` // Schema of other object members
+	AnyOf                []*Schema          `json:"anyOf,omitempty"`                // Schemas of which at least one must match
+}
+
+// OpenAPIResponse is an OpenAPI response object for a single status code.
+type OpenAPIResponse struct {
+	Description string                       `json:"description"`       // Description of the response
+	Content     map[string]*OpenAPIMediaType `json:"content,omitempty"` // Body schema per media type
+}
+
+// OpenAPIMediaType is an OpenAPI media type object.
+type OpenAPIMediaType struct {
+	Schema *Schema `json:"schema"` // Schema of the body
+}
+
+// openAPIComponentPrefix is the location of component schemas in an OpenAPI document.
+const openAPIComponentPrefix = "#/components/schemas/"
+
+// errorEnvelopeName is the component name of the envelope used by error responses.
+const errorEnvelopeName = "ErrorResponse"
+
+// OpenAPIGenerator derives OpenAPI component schemas from the Go types used with gores,
+// so that API documentation follows the code. Named struct types become components
+// referenced with $ref; the result of Components is meant to be merged into components.schemas.
+type OpenAPIGenerator struct {
+	components map[string]*Schema      // Component schemas by name
+	names      map[reflect.Type]string // Component names of the types seen so far
+	owners     map[string]reflect.Type // Types the component names were taken by
+	shape      envelopeShape           // Wire shape of the described envelopes
+}
+
+// envelopeShape is the wire shape a Renderer writes envelopes in.
+type envelopeShape struct {
+	schema       *EnvelopeSchema // Custom envelope shape, nil for the default or in bare mode
+	bare         bool            // Whether envelopes are omitted
+	bareErrorKey string          // Key wrapping bare error bodies, empty for none
+}
+
+// errorEnvelope keys the component name of the error envelope.
+type errorEnvelope struct{}
+
+// NewOpenAPIGenerator creates a new generator without components.
+func NewOpenAPIGenerator() *OpenAPIGenerator {
+	return &OpenAPIGenerator{
+		components: make(map[string]*Schema),
+		names:      make(map[reflect.Type]string),
+		owners:     make(map[string]reflect.Type),
+	}
+}
+
+// SetRenderer describes envelopes in the shape renderer writes them, i.e. with the keys of its
+// EnvelopeSchema or, in bare mode, as the data or error alone. Call it before registering anything.
+// This method uses method chaining pattern for fluent API design.
+func (g *OpenAPIGenerator) SetRenderer(renderer *Renderer) *OpenAPIGenerator {
+	g.shape = renderer.shape()
+	return g
+}
+
+// RegisterEnvelope registers the success envelope ResponseVM[T] under name and returns a
+// reference to it. An empty name derives one from T, e.g. "UserResponse" for *User, which
+// is prefixed with the package path of T when another type already took it. A name taken
+// by another type gets the first free numeric suffix, e.g. "UserResponse_2".
+// The types T refers to, as well as ResponseErrorVM and ResponseErrorFieldVM, are registered too.
+func RegisterEnvelope[T comparable](g *OpenAPIGenerator, name string) *Schema {
+	envelopeType := reflect.TypeOf(ResponseVM[T]{})
+	if name == "" {
+		dataType, suffix := indirectType(reflect.TypeOf((*T)(nil)).Elem()), "Response"
+		if dataType.Kind() == reflect.Slice || dataType.Kind() == reflect.Array {
+			dataType, suffix = indirectType(dataType.Elem()), "ListResponse"
+		}
+
+		name = componentName(dataType) + suffix
+		if owner, taken := g.owners[name]; taken && owner != envelopeType {
+			name = qualifiedComponentName(dataType) + suffix
+		}
+	}
+
+	name = g.claim(name, envelopeType)
+	g.components[name] = g.shapeEnvelope(g.structSchema(envelopeType), false)
+	return componentRef(name)
+}
+
+// ErrorEnvelope registers the envelope of error responses as "ErrorResponse" and returns
+// a reference to it. It is a ResponseVM without data whose error member is required.
+func (g *OpenAPIGenerator) ErrorEnvelope() *Schema {
+	name, exists := g.names[reflect.TypeOf(errorEnvelope{})]
+	if !exists {
+		envelope := g.structSchema(reflect.TypeOf(ResponseVM[*struct{}]{}))
+		delete(envelope.Properties, "data")
+		envelope.Required = append(envelope.Required, "error")
+
+		name = g.claim(errorEnvelopeName, reflect.TypeOf(errorEnvelope{}))
+		g.components[name] = g.shapeEnvelope(envelope, true)
+	}
+
+	return componentRef(name)
+}
+
+// SchemaFor returns the schema of the type of v, registering named struct types as components.
+// A type whose name is already taken by a type of another package is registered under its
+// name prefixed with its package path, e.g. "example.com.billing.User".
+func (g *OpenAPIGenerator) SchemaFor(v interface{}) *Schema {
+	return g.schema(reflect.TypeOf(v))
+}
+
+// Responses builds the responses object of an endpoint: the success envelope under
+// successCode and the error envelope under every declared error code. A 204 or 304
+// success code is described without a body.
+func (g *OpenAPIGenerator) Responses(successCode int, envelope *Schema, errorCodes ...int) map[string]*OpenAPIResponse {
+	responses := make(map[string]*OpenAPIResponse, len(errorCodes)+1)
+
+	success := &OpenAPIResponse{Description: http.StatusText(successCode)}
+	if successCode != http.StatusNoContent && successCode != http.StatusNotModified && envelope != nil {
+		success.Content = jsonContent(envelope)
+	}
+	responses[strconv.Itoa(successCode)] = success
+
+	errorEnvelope := g.ErrorEnvelope()
+	for _, code := range errorCodes {
+		responses[strconv.Itoa(code)] = &OpenAPIResponse{
+			Description: http.StatusText(code),
+			Content:     jsonContent(errorEnvelope),
+		}
+	}
+
+	return responses
+}
+
+// Components returns the registered component schemas keyed by name.
+func (g *OpenAPIGenerator) Components() map[string]*Schema {
+	return g.components
+}
+
+// jsonContent describes a JSON body with the given schema.
+func jsonContent(schema *Schema) map[string]*OpenAPIMediaType {
+	return map[string]*OpenAPIMediaType{
+		"application/json": {Schema: schema},
+	}
+}
+
+// componentRef returns a reference to the named component schema.
+func componentRef(name string) *Schema {
+	return &Schema{Ref: openAPIComponentPrefix + name}
+}
+
+// Types with a fixed wire representation that reflection cannot see.
+var (
+	timeType          = reflect.TypeOf(time.Time{})
+	metaType          = reflect.TypeOf(MetaVM{})
+	fieldLocationType = reflect.TypeOf(FieldLocation(""))
+	rawMessageType    = reflect.TypeOf(json.RawMessage(nil))
+	errorType         = reflect.TypeOf(ResponseErrorVM{})
+	errorFieldType    = reflect.TypeOf(ResponseErrorFieldVM{})
+	jsonMarshalerType = reflect.TypeOf((*json.Marshaler)(nil)).Elem()
+	textMarshalerType = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+)
+
+// schema returns the schema of t.
+func (g *OpenAPIGenerator) schema(t reflect.Type) *Schema {
+	if t == nil {
+		return &Schema{}
+	}
+
+	t = indirectType(t)
+
+	switch t {
+	case timeType:
+		return &Schema{Type: "string", Format: "date-time"}
+	case fieldLocationType:
+		return &Schema{Type: "string", Enum: []interface{}{LocationBody, LocationQuery, LocationPath, LocationHeader, LocationCookie}}
+	case metaType:
+		return g.component(t, func() *Schema {
+			meta := g.structSchema(t)
+			meta.AdditionalProperties = &Schema{}
+			return meta
+		})
+	case rawMessageType:
+		return &Schema{}
+	}
+
+	// Custom encodings cannot be derived; text marshalers at least produce strings.
+	// Field errors only marshal themselves to redact values and keep their struct shape.
+	if t != errorFieldType && reflect.PtrTo(t).Implements(jsonMarshalerType) {
+		return &Schema{}
+	}
+
+	if reflect.PtrTo(t).Implements(textMarshalerType) {
+		return &Schema{Type: "string"}
+	}
+
+	switch t.Kind() {
+	case reflect.Bool:
+		return &Schema{Type: "boolean"}
+	case reflect.Int8, reflect.Int16, reflect.Int32, reflect.Uint8, reflect.Uint16, reflect.Uint32:
+		return &Schema{Type: "integer", Format: "int32"}
+	case reflect.Int, reflect.Int64, reflect.Uint, reflect.Uint64:
+		return &Schema{Type: "integer", Format: "int64"}
+	case reflect.Float32:
+		return &Schema{Type: "number", Format: "float"}
+	case reflect.Float64:
+		return &Schema{Type: "number", Format: "double"}
+	case reflect.String:
+		return &Schema{Type: "string"}
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return &Schema{Type: "string", Format: "byte"}
+		}
+		return &Schema{Type: "array", Items: g.schema(t.Elem())}
+	case reflect.Map:
+		return &Schema{Type: "object", AdditionalProperties: g.schema(t.Elem())}
+	case reflect.Struct:
+		if t.Name() == "" {
+			return g.structSchema(t)
+		}
+		return g.component(t, func() *Schema { return g.structSchema(t) })
+	}
+
+	// Interfaces and other kinds accept any value
+	return &Schema{}
+}
+
+// component registers the named type t once and returns a reference to it.
+// The name is reserved before build runs so that recursive types terminate.
+func (g *OpenAPIGenerator) component(t reflect.Type, build func() *Schema) *Schema {
+	if name, exists := g.names[t]; exists {
+		return componentRef(name)
+	}
+
+	// Same-named types of different packages must not overwrite each other
+	name := componentName(t)
+	if _, taken := g.owners[name]; taken {
+		name = qualifiedComponentName(t)
+	}
+
+	name = g.claim(name, t)
+	g.components[name] = &Schema{}
+	g.components[name] = build()
+
+	return componentRef(name)
+}
+
+// claim records name as the component name of t and returns it. A name already taken by
+// another type gets the first free numeric suffix instead, e.g. "User_2".
+func (g *OpenAPIGenerator) claim(name string, t reflect.Type) string {
+	claimed := name
+	for suffix := 2; ; suffix++ {
+		if owner, taken := g.owners[claimed]; !taken || owner == t {
+			break
+		}
+		claimed = name + "_" + strconv.Itoa(suffix)
+	}
+
+	g.owners[claimed] = t
+	g.names[t] = claimed
+	return claimed
+}
+
+// structSchema describes the JSON object encoding/json produces for the struct type t.
+// Error objects get the keys of the configured EnvelopeSchema.
+func (g *OpenAPIGenerator) structSchema(t reflect.Type) *Schema {
+	schema := &Schema{Type: "object", Properties: make(map[string]*Schema)}
+	g.addFields(schema, t)
+
+	if s := g.shape.schema; s != nil && (t == errorType || t == errorFieldType) {
+		if fields := schema.Properties["error_fields"]; fields != nil && s.errorFieldsTree {
+			schema.Properties["error_fields"] = &Schema{Type: "object", AdditionalProperties: &Schema{}}
+		}
+		schema = s.renameSchema(schema)
+	}
+
+	sort.Strings(schema.Required)
+	return schema
+}
+
+// shapeEnvelope rewrites the schema of a default-shaped envelope into the configured shape.
+// In bare mode only the data is described, or the error when failed is set.
+func (g *OpenAPIGenerator) shapeEnvelope(envelope *Schema, failed bool) *Schema {
+	if g.shape.bare {
+		if !failed {
+			if data := envelope.Properties["data"]; data != nil {
+				return data
+			}
+			return &Schema{}
+		}
+
+		errorSchema := g.schema(errorType)
+		if g.shape.bareErrorKey == "" {
+			return errorSchema
+		}
+
+		return &Schema{
+			Type:       "object",
+			Properties: map[string]*Schema{g.shape.bareErrorKey: errorSchema},
+			Required:   []string{g.shape.bareErrorKey},
+		}
+	}
+
+	s := g.shape.schema
+	if s == nil {
+		return envelope
+	}
+
+	required := make(map[string]bool, len(envelope.Required))
+	for _, name := range envelope.Required {
+		required[name] = true
+	}
+
+	shaped := &Schema{Type: "object", Properties: make(map[string]*Schema)}
+	add := func(name string, property *Schema, isRequired bool) {
+		shaped.Properties[name] = property
+		if isRequired {
+			shaped.Required = append(shaped.Required, name)
+		}
+	}
+
+	for name, property := range envelope.Properties {
+		switch {
+		case name == "code" && s.omitCode:
+		case name == "error" && (s.flattenError || s.flattenErrorFields):
+			// Lifted members are described from the error component, whose keys are already renamed
+			errorObject := g.components[strings.TrimPrefix(g.schema(errorType).Ref, openAPIComponentPrefix)]
+			members := &Schema{Type: "object", Properties: make(map[string]*Schema)}
+			for key, member := range errorObject.Properties {
+				if key == s.key("error_fields") && s.flattenErrorFields {
+					add(key, member, false)
+				} else if s.flattenError {
+					add(key, member, false)
+				} else {
+					members.Properties[key] = member
+				}
+			}
+
+			for _, key := range errorObject.Required {
+				if _, lifted := shaped.Properties[key]; lifted && required[name] {
+					shaped.Required = append(shaped.Required, key)
+				} else if _, kept := members.Properties[key]; kept {
+					members.Required = append(members.Required, key)
+				}
+			}
+
+			if !s.flattenError {
+				add(s.key(name), members, required[name])
+			}
+		default:
+			add(s.key(name), property, required[name])
+		}
+	}
+
+	sort.Strings(shaped.Required)
+	return shaped
+}
+
+// addFields adds the exported fields of t to schema, inlining embedded structs.
+func (g *OpenAPIGenerator) addFields(schema *Schema, t reflect.Type) {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		tag := field.Tag.Get("json")
+		if tag == "-" || (!field.IsExported() && !field.Anonymous) {
+			continue
+		}
+
+		name, options, _ := strings.Cut(tag, ",")
+		if field.Anonymous && name == "" && indirectType(field.Type).Kind() == reflect.Struct {
+			g.addFields(schema, indirectType(field.Type))
+			continue
+		}
+
+		if !field.IsExported() {
+			continue
+		}
+
+		if name == "" {
+			name = field.Name
+		}
+
+		property := g.schema(field.Type)
+		if !strings.Contains(options, "omitempty") {
+			schema.Required = append(schema.Required, name)
+
+			// Nil pointers, slices and maps are written as null when they are not omitted
+			switch field.Type.Kind() {
+			case reflect.Pointer, reflect.Slice, reflect.Map:
+				property = &Schema{AnyOf: []*Schema{property, {Type: "null"}}}
+			}
+		}
+
+		schema.Properties[name] = property
+	}
+}
+
+// indirectType dereferences pointer types.
+func indirectType(t reflect.Type) reflect.Type {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	return t
+}
+
+// componentName derives a component name from a type name. Generic instantiations
+// such as Page[pkg.User] become Page_User.
+func componentName(t reflect.Type) string {
+	name := t.Name()
+	if name == "" {
+		return "Object"
+	}
+
+	if open := strings.IndexByte(name, '['); open >= 0 {
+		args := strings.Split(strings.TrimSuffix(name[open+1:], "]"), ",")
+		for i, arg := range args {
+			arg = strings.TrimLeft(arg, "*[]")
+			args[i] = arg[strings.LastIndexByte(arg, '.')+1:]
+		}
+		name = name[:open] + "_" + strings.Join(args, "_")
+	}
+
+	return name
+}
+
+// qualifiedComponentName prefixes the component name of t with its package path, e.g.
+// "example.com.billing.Invoice", keeping to the characters OpenAPI allows in component names.
+func qualifiedComponentName(t reflect.Type) string {
+	if t.PkgPath() == "" {
+		return componentName(t)
+	}
+
+	pkg := strings.Map(func(r rune) rune {
+		switch {
+		case r == '/':
+			return '.'
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '.', r == '-', r == '_':
+			return r
+		}
+		return '_'
+	}, t.PkgPath())
+
+	return pkg + "." + componentName(t)
+}
@@ -0,0 +1,235 @@
+package gores
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+)
+
+type openAPIUser struct {
+	ID        int64             `json:"id"`
+	Name      string            `json:"name"`
+	Email     string            `json:"email,omitempty"`
+	Tags      []string          `json:"tags,omitempty"`
+	Labels    map[string]string `json:"labels,omitempty"`
+	Manager   *openAPIUser      `json:"manager,omitempty"`
+	CreatedAt time.Time         `json:"created_at"`
+	secret    string
+	Ignored   string `json:"-"`
+}
+
+func TestRegisterEnvelope(t *testing.T) {
+	generator := NewOpenAPIGenerator()
+
+	testCases := []struct {
+		Name     string
+		Register func() *Schema
+		Expected string
+	}{
+		{
+			Name:     "DerivedName",
+			Register: func() *Schema { return RegisterEnvelope[*openAPIUser](generator, "") },
+			Expected: "#/components/schemas/openAPIUserResponse",
+		},
+		{
+			Name:     "DerivedListName",
+			Register: func() *Schema { return RegisterEnvelope[*[]openAPIUser](generator, "") },
+			Expected: "#/components/schemas/openAPIUserListResponse",
+		},
+		{
+			Name:     "ExplicitName",
+			Register: func() *Schema { return RegisterEnvelope[*openAPIUser](generator, "UserEnvelope") },
+			Expected: "#/components/schemas/UserEnvelope",
+		},
+	}
+
+	for i := range testCases {
+		t.Run(testCases[i].Name, func(t *testing.T) {
+			if ref := testCases[i].Register().Ref; ref != testCases[i].Expected {
+				t.Errorf("expected ref is %s, got %s", testCases[i].Expected, ref)
+			}
+		})
+	}
+
+	components := generator.Components()
+	for _, name := range []string{"openAPIUser", "ResponseErrorVM", "ResponseErrorFieldVM", "ResponseWarningVM", "MetaVM"} {
+		if components[name] == nil {
+			t.Errorf("expected component %s to be registered", name)
+		}
+	}
+
+	envelope := components["UserEnvelope"]
+	if envelope.Properties["data"].Ref != "#/components/schemas/openAPIUser" {
+		t.Errorf("expected data to reference the user component, got %+v", envelope.Properties["data"])
+	}
+
+	if len(envelope.Required) != 1 || envelope.Required[0] != "code" {
+		t.Errorf("expected only code to be required, got %v", envelope.Required)
+	}
+
+	user, _ := json.Marshal(components["openAPIUser"])
+	expected := `{"type":"object","properties":{"created_at":{"type":"string","format":"date-time"},"email":{"type":"string"},"id":{"type":"integer","format":"int64"},"labels":{"type":"object","additionalProperties":{"type":"string"}},"manager":{"$ref":"#/components/schemas/openAPIUser"},"name":{"type":"string"},"tags":{"type":"array","items":{"type":"string"}}},"required":["created_at","id","name"]}`
+	if string(user) != expected {
+		t.Errorf("expected user schema is %s, got %s", expected, string(user))
+	}
+
+	field := components["ResponseErrorFieldVM"]
+	if len(field.Properties["location"].Enum) != 5 {
+		t.Errorf("expected location enum to list 5 locations, got %v", field.Properties["location"].Enum)
+	}
+}
+
+func TestOpenAPIGenerator_Responses(t *testing.T) {
+	generator := NewOpenAPIGenerator()
+	envelope := RegisterEnvelope[*openAPIUser](generator, "UserResponse")
+
+	responses := generator.Responses(http.StatusOK, envelope, http.StatusNotFound, http.StatusUnprocessableEntity)
+
+	if len(responses) != 3 {
+		t.Fatalf("expected length of responses is 3, got %d", len(responses))
+	}
+
+	if responses["200"].Content["application/json"].Schema != envelope {
+		t.Error("expected success response to use the envelope schema")
+	}
+
+	notFound := responses["404"]
+	if notFound.Description != "Not Found" || notFound.Content["application/json"].Schema.Ref != "#/components/schemas/ErrorResponse" {
+		t.Errorf("unexpected not found response %+v", notFound)
+	}
+
+	errorEnvelope := generator.Components()["ErrorResponse"]
+	if errorEnvelope.Properties["data"] != nil {
+		t.Error("expected error envelope not to describe data")
+	}
+
+	if len(errorEnvelope.Required) != 2 || errorEnvelope.Required[1] != "error" {
+		t.Errorf("expected code and error to be required, got %v", errorEnvelope.Required)
+	}
+
+	noContent := generator.Responses(http.StatusNoContent, nil)
+	if noContent["204"].Content != nil {
+		t.Error("expected 204 response without content")
+	}
+}
+
+// Cookie has the same name as http.Cookie
+type Cookie struct {
+	Flavor string `json:"flavor"`
+}
+
+func TestOpenAPIGenerator_NameCollision(t *testing.T) {
+	g := NewOpenAPIGenerator()
+
+	local := g.SchemaFor(Cookie{})
+	remote := g.SchemaFor(http.Cookie{})
+	localEnvelope := RegisterEnvelope[*Cookie](g, "")
+	remoteEnvelope := RegisterEnvelope[*http.Cookie](g, "")
+
+	testCases := []struct {
+		Name         string
+		Schema       *Schema
+		ExpectedRef  string
+		ExpectedProp string
+	}{
+		{Name: "FirstKeepsName", Schema: local, ExpectedRef: "#/components/schemas/Cookie", ExpectedProp: "flavor"},
+		{Name: "SecondQualified", Schema: remote, ExpectedRef: "#/components/schemas/net.http.Cookie", ExpectedProp: "Name"},
+		{Name: "FirstEnvelopeKeepsName", Schema: localEnvelope, ExpectedRef: "#/components/schemas/CookieResponse", ExpectedProp: "data"},
+		{Name: "SecondEnvelopeQualified", Schema: remoteEnvelope, ExpectedRef: "#/components/schemas/net.http.CookieResponse", ExpectedProp: "data"},
+	}
+
+	for i := range testCases {
+		t.Run(testCases[i].Name, func(t *testing.T) {
+			if testCases[i].Schema.Ref != testCases[i].ExpectedRef {
+				t.Fatalf("expected ref is %s, got %s", testCases[i].ExpectedRef, testCases[i].Schema.Ref)
+			}
+
+			component := g.Components()[testCases[i].Schema.Ref[len(openAPIComponentPrefix):]]
+			if component == nil || component.Properties[testCases[i].ExpectedProp] == nil {
+				t.Errorf("expected component with property %s, got %+v", testCases[i].ExpectedProp, component)
+			}
+		})
+	}
+
+	// Registering a type again reuses its component
+	if again := g.SchemaFor(http.Cookie{}); again.Ref != remote.Ref {
+		t.Errorf("expected ref is %s, got %s", remote.Ref, again.Ref)
+	}
+}
+
+func TestRegisterEnvelope_ExplicitNameTaken(t *testing.T) {
+	g := NewOpenAPIGenerator()
+
+	user := RegisterEnvelope[*openAPIUser](g, "Envelope")
+	cookie := RegisterEnvelope[*Cookie](g, "Envelope")
+	again := RegisterEnvelope[*openAPIUser](g, "Envelope")
+
+	if user.Ref != "#/components/schemas/Envelope" || again.Ref != user.Ref {
+		t.Errorf("expected the user envelope to keep its name, got %s and %s", user.Ref, again.Ref)
+	}
+
+	if cookie.Ref != "#/components/schemas/Envelope_2" {
+		t.Errorf("expected ref is %s, got %s", "#/components/schemas/Envelope_2", cookie.Ref)
+	}
+
+	if data := g.Components()["Envelope"].Properties["data"]; data.Ref != "#/components/schemas/openAPIUser" {
+		t.Errorf("expected the user envelope not to be overwritten, got %+v", data)
+	}
+}
+
+func TestOpenAPIGenerator_SetRenderer(t *testing.T) {
+	testCases := []struct {
+		Name                  string
+		Renderer              *Renderer
+		ExpectedEnvelope      string
+		ExpectedErrorEnvelope string
+		ExpectedError         string
+	}{
+		{
+			Name: "Schema",
+			Renderer: NewRenderer().SetSchema(NewEnvelopeSchema().
+				SetKey("data", "result").
+				SetKey("message", "detail").
+				SetKeyCase(CamelCase).
+				SetOmitCode(true).
+				SetFlattenErrorFields(true)),
+			ExpectedEnvelope:      `{"type":"object","properties":{"error":{"type":"object","properties":{"conflict":{"$ref":"#/components/schemas/ResponseConflictVM"},"detail":{"type":"string"},"errorCode":{"type":"string"},"path":{"type":"string"},"retryAfter":{"type":"integer","format":"int64"},"retryable":{"type":"boolean"}},"required":["detail"]},"errorFields":{"type":"array","items":{"$ref":"#/components/schemas/ResponseErrorFieldVM"}},"errors":{"type":"array","items":{"$ref":"#/components/schemas/ResponseErrorVM"}},"meta":{"$ref":"#/components/schemas/MetaVM"},"result":{"$ref":"#/components/schemas/openAPIUser"},"warnings":{"type":"array","items":{"$ref":"#/components/schemas/ResponseWarningVM"}}}}`,
+			ExpectedErrorEnvelope: `{"type":"object","properties":{"error":{"type":"object","properties":{"conflict":{"$ref":"#/components/schemas/ResponseConflictVM"},"detail":{"type":"string"},"errorCode":{"type":"string"},"path":{"type":"string"},"retryAfter":{"type":"integer","format":"int64"},"retryable":{"type":"boolean"}},"required":["detail"]},"errorFields":{"type":"array","items":{"$ref":"#/components/schemas/ResponseErrorFieldVM"}},"errors":{"type":"array","items":{"$ref":"#/components/schemas/ResponseErrorVM"}},"meta":{"$ref":"#/components/schemas/MetaVM"},"warnings":{"type":"array","items":{"$ref":"#/components/schemas/ResponseWarningVM"}}},"required":["error"]}`,
+			ExpectedError:         `{"type":"object","properties":{"conflict":{"$ref":"#/components/schemas/ResponseConflictVM"},"detail":{"type":"string"},"errorCode":{"type":"string"},"errorFields":{"type":"array","items":{"$ref":"#/components/schemas/ResponseErrorFieldVM"}},"path":{"type":"string"},"retryAfter":{"type":"integer","format":"int64"},"retryable":{"type":"boolean"}},"required":["detail"]}`,
+		},
+		{
+			Name:                  "Bare",
+			Renderer:              NewRenderer().SetBareMode(true).SetBareErrorKey("error"),
+			ExpectedEnvelope:      `{"$ref":"#/components/schemas/openAPIUser"}`,
+			ExpectedErrorEnvelope: `{"type":"object","properties":{"error":{"$ref":"#/components/schemas/ResponseErrorVM"}},"required":["error"]}`,
+		},
+	}
+
+	for i := range testCases {
+		t.Run(testCases[i].Name, func(t *testing.T) {
+			g := NewOpenAPIGenerator().SetRenderer(testCases[i].Renderer)
+			RegisterEnvelope[*openAPIUser](g, "UserResponse")
+			g.ErrorEnvelope()
+
+			envelope, _ := json.Marshal(g.Components()["UserResponse"])
+			if string(envelope) != testCases[i].ExpectedEnvelope {
+				t.Errorf("expected envelope schema is\n%s\ngot\n%s", testCases[i].ExpectedEnvelope, envelope)
+			}
+
+			errorEnvelope, _ := json.Marshal(g.Components()["ErrorResponse"])
+			if string(errorEnvelope) != testCases[i].ExpectedErrorEnvelope {
+				t.Errorf("expected error envelope schema is\n%s\ngot\n%s", testCases[i].ExpectedErrorEnvelope, errorEnvelope)
+			}
+
+			if testCases[i].ExpectedError == "" {
+				return
+			}
+
+			errorSchema, _ := json.Marshal(g.Components()["ResponseErrorVM"])
+			if string(errorSchema) != testCases[i].ExpectedError {
+				t.Errorf("expected error schema is\n%s\ngot\n%s", testCases[i].ExpectedError, errorSchema)
+			}
+		})
+	}
+}
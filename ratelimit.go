@@ -0,0 +1,340 @@
+package gores
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"net"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// ErrorCodeQuotaExceeded is the error code of responses rejected by the RateLimit middleware.
+const ErrorCodeQuotaExceeded = "QUOTA_EXCEEDED"
+
+// RateLimitResult describes the quota state of a key after a request was counted.
+type RateLimitResult struct {
+	Allowed    bool          // Whether the request may proceed
+	Limit      int           // Maximum number of requests per window
+	Remaining  int           // Requests left in the current window
+	Reset      time.Duration // Time until the quota is fully replenished
+	RetryAfter time.Duration // Time until the next request is allowed when denied
+}
+
+// Limiter decides whether a request identified by key is allowed.
+// Implementations must be safe for concurrent use.
+type Limiter interface {
+	Allow(key string) RateLimitResult
+}
+
+// rateLimitContextKey is the context key under which the RateLimitResult is stored.
+type rateLimitContextKey struct{}
+
+// RateLimitFromContext returns the quota state computed by the RateLimit middleware.
+// Handlers can use it to expose remaining quota in the response body.
+func RateLimitFromContext(ctx context.Context) (RateLimitResult, bool) {
+	result, ok := ctx.Value(rateLimitContextKey{}).(RateLimitResult)
+	return result, ok
+}
+
+// RateLimit is a middleware that enforces a Limiter and speaks the gores envelope.
+// Rejected requests receive a 429 ResponseVM with ErrorCodeQuotaExceeded, and every
+// response carries RateLimit-Limit, RateLimit-Remaining and RateLimit-Reset headers.
+type RateLimit struct {
+	limiter  Limiter
+	renderer *Renderer
+	keyFunc  func(r *http.Request) string
+}
+
+// NewRateLimit creates a new RateLimit middleware enforcing limiter.
+// Requests are keyed by client IP address unless SetKeyFunc is used.
+func NewRateLimit(limiter Limiter) *RateLimit {
+	return &RateLimit{
+		limiter:  limiter,
+		renderer: NewRenderer(),
+		keyFunc:  KeyByIP,
+	}
+}
+
+// SetKeyFunc sets the function that derives the quota key from a request.
+// This method uses method chaining pattern for fluent API design.
+func (m *RateLimit) SetKeyFunc(keyFunc func(r *http.Request) string) *RateLimit {
+	m.keyFunc = keyFunc
+	return m
+}
+
+// SetRenderer sets the Renderer used for 429 responses.
+// This method uses method chaining pattern for fluent API design.
+func (m *RateLimit) SetRenderer(renderer *Renderer) *RateLimit {
+	m.renderer = renderer
+	return m
+}
+
+// Handler wraps next with rate limiting.
+func (m *RateLimit) Handler(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		result := m.limiter.Allow(m.keyFunc(r))
+
+		header := w.Header()
+		header.Set("RateLimit-Limit", strconv.Itoa(result.Limit))
+		header.Set("RateLimit-Remaining", strconv.Itoa(result.Remaining))
+		header.Set("RateLimit-Reset", strconv.Itoa(ceilSeconds(result.Reset)))
+
+		if !result.Allowed {
+			// The Renderer mirrors the error's retry delay in the Retry-After header
+			response := NewResponseVM[*struct{}]().
+				SetCode(http.StatusTooManyRequests).
+				SetError(
+					NewResponseErrorVM().
+						SetMessage(fmt.Sprintf("rate limit exceeded, retry in %d seconds", ceilSeconds(result.RetryAfter))).
+						SetErrorCode(ErrorCodeQuotaExceeded).
+						SetRetryAfter(result.RetryAfter),
+				)
+
+			m.renderer.Render(w, r, response)
+			return
+		}
+
+		next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), rateLimitContextKey{}, result)))
+	})
+}
+
+// KeyByIP derives the quota key from the client IP address in RemoteAddr.
+// Deployments behind a proxy should supply a key function that trusts their forwarding header.
+func KeyByIP(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+
+	return host
+}
+
+// KeyByHeader returns a key function that uses the value of the named header,
+// such as an API key. Requests without the header share a single anonymous quota.
+func KeyByHeader(name string) func(r *http.Request) string {
+	return func(r *http.Request) string {
+		return r.Header.Get(name)
+	}
+}
+
+// KeyByRoute returns a key function that scopes another key function by method and path.
+func KeyByRoute(keyFunc func(r *http.Request) string) func(r *http.Request) string {
+	return func(r *http.Request) string {
+		return r.Method + " " + r.URL.Path + " " + keyFunc(r)
+	}
+}
+
+// ceilSeconds rounds a duration up to whole seconds for header values.
+func ceilSeconds(d time.Duration) int {
+	if d <= 0 {
+		return 0
+	}
+
+	return int((d + time.Second - 1) / time.Second)
+}
+
+// mustBePositiveLimit panics on limiter settings that would divide by zero or never allow a request.
+func mustBePositiveLimit(limit int, window time.Duration) {
+	if limit <= 0 || window <= 0 {
+		panic(fmt.Sprintf("gores: rate limit needs a positive limit and window, got %d per %s", limit, window))
+	}
+}
+
+// TokenBucketLimiter allows bursts of up to limit requests and refills
+// limit tokens evenly over each window.
+type TokenBucketLimiter struct {
+	mu        sync.Mutex
+	limit     int
+	window    time.Duration
+	buckets   map[string]*tokenBucket
+	lastSweep time.Time
+	now       func() time.Time
+}
+
+// tokenBucket is the state of a single key in a TokenBucketLimiter.
+type tokenBucket struct {
+	tokens float64
+	last   time.Time
+}
+
+// NewTokenBucketLimiter creates a token bucket limiter allowing limit requests per window.
+// It panics when limit or window is not positive.
+func NewTokenBucketLimiter(limit int, window time.Duration) *TokenBucketLimiter {
+	mustBePositiveLimit(limit, window)
+
+	return &TokenBucketLimiter{
+		limit:   limit,
+		window:  window,
+		buckets: make(map[string]*tokenBucket),
+		now:     time.Now,
+	}
+}
+
+// Allow consumes a token for key if one is available.
+func (l *TokenBucketLimiter) Allow(key string) RateLimitResult {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.now()
+	l.sweep(now)
+
+	capacity := float64(l.limit)
+	rate := capacity / l.window.Seconds() // tokens per second
+
+	bucket, exists := l.buckets[key]
+	if !exists {
+		bucket = &tokenBucket{tokens: capacity, last: now}
+		l.buckets[key] = bucket
+	}
+
+	// Refill proportionally to the time elapsed since the last request
+	bucket.tokens = math.Min(capacity, bucket.tokens+now.Sub(bucket.last).Seconds()*rate)
+	bucket.last = now
+
+	result := RateLimitResult{Limit: l.limit}
+	if bucket.tokens >= 1 {
+		bucket.tokens--
+		result.Allowed = true
+	} else {
+		result.RetryAfter = secondsToDuration((1 - bucket.tokens) / rate)
+	}
+
+	result.Remaining = int(bucket.tokens)
+	result.Reset = secondsToDuration((capacity - bucket.tokens) / rate)
+
+	return result
+}
+
+// sweep evicts buckets that have been full for a whole window. The caller must hold the lock.
+func (l *TokenBucketLimiter) sweep(now time.Time) {
+	if now.Sub(l.lastSweep) < l.window {
+		return
+	}
+
+	l.lastSweep = now
+	for key, bucket := range l.buckets {
+		if now.Sub(bucket.last) >= l.window {
+			delete(l.buckets, key)
+		}
+	}
+}
+
+// SlidingWindowLimiter allows limit requests in any rolling window, approximated
+// by weighting the previous fixed window by its overlap with the rolling one.
+type SlidingWindowLimiter struct {
+	mu        sync.Mutex
+	limit     int
+	window    time.Duration
+	counters  map[string]*slidingWindow
+	lastSweep time.Time
+	now       func() time.Time
+}
+
+// slidingWindow is the state of a single key in a SlidingWindowLimiter.
+type slidingWindow struct {
+	start    time.Time
+	current  int
+	previous int
+}
+
+// NewSlidingWindowLimiter creates a sliding window limiter allowing limit requests per window.
+// It panics when limit or window is not positive.
+func NewSlidingWindowLimiter(limit int, window time.Duration) *SlidingWindowLimiter {
+	mustBePositiveLimit(limit, window)
+
+	return &SlidingWindowLimiter{
+		limit:    limit,
+		window:   window,
+		counters: make(map[string]*slidingWindow),
+		now:      time.Now,
+	}
+}
+
+// Allow counts a request for key if the rolling window has capacity left.
+func (l *SlidingWindowLimiter) Allow(key string) RateLimitResult {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.now()
+	l.sweep(now)
+
+	counter, exists := l.counters[key]
+	if !exists {
+		counter = &slidingWindow{start: now.Truncate(l.window)}
+		l.counters[key] = counter
+	}
+
+	// Roll fixed windows forward, dropping counts older than one window
+	if elapsed := now.Sub(counter.start); elapsed >= l.window {
+		windows := int(elapsed / l.window)
+		counter.start = counter.start.Add(time.Duration(windows) * l.window)
+		counter.previous = 0
+		if windows == 1 {
+			counter.previous = counter.current
+		}
+		counter.current = 0
+	}
+
+	elapsed := now.Sub(counter.start)
+	weight := 1 - elapsed.Seconds()/l.window.Seconds()
+	estimated := float64(counter.previous)*weight + float64(counter.current)
+
+	result := RateLimitResult{
+		Limit: l.limit,
+		Reset: l.window - elapsed,
+	}
+
+	if estimated+1 <= float64(l.limit) {
+		counter.current++
+		estimated++
+		result.Allowed = true
+	} else {
+		result.RetryAfter = l.retryAfter(counter, elapsed)
+	}
+
+	result.Remaining = int(math.Max(0, math.Floor(float64(l.limit)-estimated)))
+
+	return result
+}
+
+// retryAfter computes when the decaying previous window frees up a slot.
+func (l *SlidingWindowLimiter) retryAfter(counter *slidingWindow, elapsed time.Duration) time.Duration {
+	remainder := l.window - elapsed
+
+	// When the current window alone exhausts the limit, wait for it to roll over
+	if counter.previous == 0 || counter.current >= l.limit {
+		return remainder
+	}
+
+	// Solve previous*weight+current <= limit-1 for the time the weight needs to decay
+	free := float64(l.limit-1-counter.current) / float64(counter.previous)
+
+	wait := secondsToDuration((1-free)*l.window.Seconds()) - elapsed
+	if wait <= 0 || wait > remainder {
+		return remainder
+	}
+
+	return wait
+}
+
+// sweep evicts counters idle for two windows. The caller must hold the lock.
+func (l *SlidingWindowLimiter) sweep(now time.Time) {
+	if now.Sub(l.lastSweep) < l.window {
+		return
+	}
+
+	l.lastSweep = now
+	for key, counter := range l.counters {
+		if now.Sub(counter.start) >= 2*l.window {
+			delete(l.counters, key)
+		}
+	}
+}
+
+// secondsToDuration converts fractional seconds to a duration.
+func secondsToDuration(seconds float64) time.Duration {
+	return time.Duration(seconds * float64(time.Second))
+}
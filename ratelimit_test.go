@@ -0,0 +1,182 @@
+package gores
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestTokenBucketLimiter(t *testing.T) {
+	now := time.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC)
+	limiter := NewTokenBucketLimiter(2, 10*time.Second)
+	limiter.now = func() time.Time { return now }
+
+	testCases := []struct {
+		Name              string
+		Advance           time.Duration
+		ExpectedAllowed   bool
+		ExpectedRemaining int
+	}{
+		{Name: "First", ExpectedAllowed: true, ExpectedRemaining: 1},
+		{Name: "Second", ExpectedAllowed: true, ExpectedRemaining: 0},
+		{Name: "Exhausted", ExpectedAllowed: false, ExpectedRemaining: 0},
+		{Name: "Refilled", Advance: 5 * time.Second, ExpectedAllowed: true, ExpectedRemaining: 0},
+	}
+
+	for i := range testCases {
+		now = now.Add(testCases[i].Advance)
+		result := limiter.Allow("client")
+
+		if result.Allowed != testCases[i].ExpectedAllowed {
+			t.Errorf("%s: expected allowed is %t, got %t", testCases[i].Name, testCases[i].ExpectedAllowed, result.Allowed)
+		}
+
+		if result.Remaining != testCases[i].ExpectedRemaining {
+			t.Errorf("%s: expected remaining is %d, got %d", testCases[i].Name, testCases[i].ExpectedRemaining, result.Remaining)
+		}
+
+		if !result.Allowed && result.RetryAfter != 5*time.Second {
+			t.Errorf("%s: expected retry after is %s, got %s", testCases[i].Name, 5*time.Second, result.RetryAfter)
+		}
+	}
+}
+
+func TestSlidingWindowLimiter(t *testing.T) {
+	now := time.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC)
+	limiter := NewSlidingWindowLimiter(2, 10*time.Second)
+	limiter.now = func() time.Time { return now }
+
+	testCases := []struct {
+		Name            string
+		Advance         time.Duration
+		ExpectedAllowed bool
+	}{
+		{Name: "First", ExpectedAllowed: true},
+		{Name: "Second", ExpectedAllowed: true},
+		{Name: "Exhausted", ExpectedAllowed: false},
+		{Name: "PreviousWindowStillWeighs", Advance: 12 * time.Second, ExpectedAllowed: false},
+		{Name: "PreviousWindowDecayed", Advance: 4 * time.Second, ExpectedAllowed: true},
+		{Name: "OtherKeyUnaffected", ExpectedAllowed: true},
+	}
+
+	for i := range testCases {
+		now = now.Add(testCases[i].Advance)
+
+		key := "client"
+		if testCases[i].Name == "OtherKeyUnaffected" {
+			key = "other"
+		}
+
+		result := limiter.Allow(key)
+		if result.Allowed != testCases[i].ExpectedAllowed {
+			t.Errorf("%s: expected allowed is %t, got %t", testCases[i].Name, testCases[i].ExpectedAllowed, result.Allowed)
+		}
+
+		if !result.Allowed && result.RetryAfter <= 0 {
+			t.Errorf("%s: expected positive retry after, got %s", testCases[i].Name, result.RetryAfter)
+		}
+	}
+}
+
+func TestLimiters_RejectNonPositiveSettings(t *testing.T) {
+	constructors := map[string]func(limit int, window time.Duration){
+		"TokenBucket":   func(limit int, window time.Duration) { NewTokenBucketLimiter(limit, window) },
+		"SlidingWindow": func(limit int, window time.Duration) { NewSlidingWindowLimiter(limit, window) },
+	}
+
+	testCases := []struct {
+		Name   string
+		Limit  int
+		Window time.Duration
+	}{
+		{Name: "ZeroLimit", Limit: 0, Window: time.Second},
+		{Name: "NegativeLimit", Limit: -1, Window: time.Second},
+		{Name: "ZeroWindow", Limit: 1, Window: 0},
+		{Name: "NegativeWindow", Limit: 1, Window: -time.Second},
+	}
+
+	for name, construct := range constructors {
+		for i := range testCases {
+			t.Run(name+"/"+testCases[i].Name, func(t *testing.T) {
+				defer func() {
+					if recover() == nil {
+						t.Errorf("expected a panic for %d per %s", testCases[i].Limit, testCases[i].Window)
+					}
+				}()
+
+				construct(testCases[i].Limit, testCases[i].Window)
+			})
+		}
+	}
+}
+
+func TestRateLimit_Handler(t *testing.T) {
+	var remaining int
+	handler := NewRateLimit(NewTokenBucketLimiter(1, time.Minute)).
+		SetKeyFunc(KeyByHeader("X-API-Key")).
+		Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			result, _ := RateLimitFromContext(r.Context())
+			remaining = result.Remaining
+			w.WriteHeader(http.StatusOK)
+		}))
+
+	testCases := []struct {
+		Name              string
+		APIKey            string
+		ExpectedCode      int
+		ExpectedRemaining string
+		ExpectRetryAfter  bool
+	}{
+		{Name: "Allowed", APIKey: "a", ExpectedCode: http.StatusOK, ExpectedRemaining: "0"},
+		{Name: "Limited", APIKey: "a", ExpectedCode: http.StatusTooManyRequests, ExpectedRemaining: "0", ExpectRetryAfter: true},
+		{Name: "OtherKey", APIKey: "b", ExpectedCode: http.StatusOK, ExpectedRemaining: "0"},
+	}
+
+	for i := range testCases {
+		t.Run(testCases[i].Name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodGet, "/", nil)
+			r.Header.Set("X-API-Key", testCases[i].APIKey)
+			w := httptest.NewRecorder()
+			handler.ServeHTTP(w, r)
+
+			if w.Code != testCases[i].ExpectedCode {
+				t.Errorf("expected code is %d, got %d", testCases[i].ExpectedCode, w.Code)
+			}
+
+			if w.Header().Get("RateLimit-Limit") != "1" {
+				t.Errorf("expected limit is 1, got %s", w.Header().Get("RateLimit-Limit"))
+			}
+
+			if w.Header().Get("RateLimit-Remaining") != testCases[i].ExpectedRemaining {
+				t.Errorf("expected remaining is %s, got %s", testCases[i].ExpectedRemaining, w.Header().Get("RateLimit-Remaining"))
+			}
+
+			if (w.Header().Get("Retry-After") != "") != testCases[i].ExpectRetryAfter {
+				t.Errorf("expected retry after presence is %t, got %q", testCases[i].ExpectRetryAfter, w.Header().Get("Retry-After"))
+			}
+
+			if testCases[i].ExpectedCode == http.StatusTooManyRequests && !strings.Contains(w.Body.String(), `"error_code":"QUOTA_EXCEEDED"`) {
+				t.Errorf("expected quota exceeded error code, got %s", w.Body.String())
+			}
+
+			if testCases[i].ExpectedCode == http.StatusOK && remaining != 0 {
+				t.Errorf("expected remaining in context is 0, got %d", remaining)
+			}
+		})
+	}
+}
+
+func TestKeyFuncs(t *testing.T) {
+	r := httptest.NewRequest(http.MethodPost, "/orders", nil)
+	r.RemoteAddr = "203.0.113.7:51234"
+
+	if key := KeyByIP(r); key != "203.0.113.7" {
+		t.Errorf("expected key is %s, got %s", "203.0.113.7", key)
+	}
+
+	if key := KeyByRoute(KeyByIP)(r); key != "POST /orders 203.0.113.7" {
+		t.Errorf("expected key is %s, got %s", "POST /orders 203.0.113.7", key)
+	}
+}
@@ -0,0 +1,403 @@
+package gores
+
+import (
+	"encoding/json"
+	"net/http"
+	"reflect"
+	"strconv"
+	"time"
+)
+
+// Envelope is implemented by response models that a Renderer can write.
+// It is satisfied by *ResponseVM[T] for any T.
+type Envelope interface {
+	statusCode() int
+	errorVM() *ResponseErrorVM
+	payload() interface{}
+	withMeta(meta *MetaVM) Envelope
+	warnings() []*ResponseWarningVM
+	withWarnings(warnings []*ResponseWarningVM) Envelope
+	headers() http.Header
+	validators() (string, time.Time)
+}
+
+// Renderer writes gores envelopes to HTTP responses as JSON.
+// It centralizes header handling so that every endpoint produces the same wire format.
+// A zero-value Renderer is usable; NewRenderer returns one with default settings.
+type Renderer struct {
+	etagMode     ETagMode           // How ETags are derived for successful responses
+	schema       *EnvelopeSchema    // Custom envelope shape, nil for the default
+	bare         bool               // Whether envelopes are omitted
+	bareErrorKey string             // Key wrapping bare error bodies, empty for none
+	validator    *ResponseValidator // Contract check run before writing, nil for none
+	canonical    bool               // Whether bodies are encoded as RFC 8785 canonical JSON
+	signer       *ResponseSigner    // Signs every body in a header, nil for none
+	digests      []DigestAlgorithm  // Content-Digest algorithms, empty for none
+	compression  *Compression       // Negotiated body compression, nil for none
+	fieldsParam  string             // Query parameter selecting sparse fieldsets, empty for none
+	strategy     StatusStrategy     // Status code of multi-error trees in RenderError, nil for the default
+}
+
+// NewRenderer creates a new Renderer with default settings.
+// By default no ETag is computed unless the envelope carries a caller-supplied version.
+func NewRenderer() *Renderer {
+	return &Renderer{}
+}
+
+// SetETagMode sets how the Renderer derives ETags for successful responses.
+// This method uses method chaining pattern for fluent API design.
+func (rd *Renderer) SetETagMode(mode ETagMode) *Renderer {
+	rd.etagMode = mode
+	return rd
+}
+
+// SetStatusStrategy sets how RenderError picks the status code of errors that carry
+// several gocerr.Error values. The default is StatusMostSevere; nil restores it.
+// This method uses method chaining pattern for fluent API design.
+func (rd *Renderer) SetStatusStrategy(strategy StatusStrategy) *Renderer {
+	rd.strategy = strategy
+	return rd
+}
+
+// SetSchema sets a custom envelope shape applied to every rendered body.
+// This method uses method chaining pattern for fluent API design.
+func (rd *Renderer) SetSchema(schema *EnvelopeSchema) *Renderer {
+	rd.schema = schema
+	return rd
+}
+
+// SetBareMode switches to envelope-less rendering.
+// Successful responses then carry only Data as their body, and failed responses carry
+// only the ResponseErrorVM, optionally wrapped under the key set by SetBareErrorKey.
+// The status code is conveyed by the HTTP status line alone and the schema is not applied.
+func (rd *Renderer) SetBareMode(bare bool) *Renderer {
+	rd.bare = bare
+	return rd
+}
+
+// SetBareErrorKey wraps bare error bodies in an object under key, e.g. {"error":{...}}.
+// An empty key writes the ResponseErrorVM itself. It only applies in bare mode.
+func (rd *Renderer) SetBareErrorKey(key string) *Renderer {
+	rd.bareErrorKey = key
+	return rd
+}
+
+// SetValidator sets a validator that checks every encoded body against its JSON Schema
+// before it is written. Leave it unset, or use ValidationOff, in production.
+func (rd *Renderer) SetValidator(validator *ResponseValidator) *Renderer {
+	rd.validator = validator
+	return rd
+}
+
+// SetCanonical encodes every body in the RFC 8785 JSON Canonicalization Scheme,
+// so that equal envelopes are byte-identical. Canonical bodies are always compact.
+func (rd *Renderer) SetCanonical(canonical bool) *Renderer {
+	rd.canonical = canonical
+	return rd
+}
+
+// SetSigner signs every body with a detached JWS written to the signer's header.
+// Signed bodies are always encoded canonically, as with SetCanonical(true).
+func (rd *Renderer) SetSigner(signer *ResponseSigner) *Renderer {
+	rd.signer = signer
+	return rd
+}
+
+// SetContentDigest adds an RFC 9530 Content-Digest header computed over every body
+// with the given algorithms. Calling it without algorithms disables the header.
+func (rd *Renderer) SetContentDigest(algorithms ...DigestAlgorithm) *Renderer {
+	rd.digests = algorithms
+	return rd
+}
+
+// SetCompression compresses bodies with the coding negotiated from Accept-Encoding.
+// Strong ETags get a suffix per coding, whether computed from the body or set via
+// ResponseVM.SetVersion, e.g. "42-gzip"; Renderer.CheckPreconditions accepts them in If-Match.
+func (rd *Renderer) SetCompression(compression *Compression) *Renderer {
+	rd.compression = compression
+	return rd
+}
+
+// SetFieldsParam enables sparse fieldsets selected with the query parameter param, usually
+// "fields": ?fields=id,name,owner.email keeps only those members of Data, in objects and
+// arrays of objects alike. Fields the type of Data cannot have are rejected with a 400.
+func (rd *Renderer) SetFieldsParam(param string) *Renderer {
+	rd.fieldsParam = param
+	return rd
+}
+
+// Render encodes the envelope and writes it to w with its status code.
+// For successful GET and HEAD requests it emits ETag and Last-Modified validators and
+// answers If-None-Match / If-Modified-Since with 304 Not Modified and an empty body.
+// A zero status code is written as HTTP 200 OK. The request may be nil when no
+// conditional handling is required. Nothing is written when the request context was
+// canceled because the client disconnected; the context error is returned instead.
+func (rd *Renderer) Render(w http.ResponseWriter, r *http.Request, vm Envelope) error {
+	// Nobody is listening once the client has disconnected
+	if isClientGone(r) {
+		return r.Context().Err()
+	}
+
+	// Sparse fieldsets only apply to successful responses and are validated against the data type
+	fields := rd.fieldSelection(r)
+	if vm.errorVM() != nil || vm.statusCode() >= http.StatusBadRequest {
+		fields = nil
+	} else if unknown := fields.unknown(reflect.TypeOf(vm.payload()), ""); len(unknown) > 0 {
+		return rd.Render(w, r, NewResponseVM[*struct{}]().
+			SetCode(http.StatusBadRequest).
+			SetError(unknownFieldsError(rd.fieldsParam, unknown)))
+	}
+
+	// Metadata and warnings collected from the request context are attached before encoding
+	var serverTiming string
+	var etagBody []byte
+	if r != nil {
+		vm = vm.withWarnings(WarningsFromContext(r.Context()))
+
+		if collector := metaCollectorFromContext(r.Context()); collector != nil {
+			// Collected metadata such as the timestamp changes on every request, so a
+			// computed ETag covers the body as it is before the metadata is merged
+			if rd.computesETag(vm) {
+				var err error
+				if etagBody, err = rd.encode(vm, fields); err != nil {
+					return err
+				}
+			}
+
+			var meta *MetaVM
+			meta, serverTiming = collector.build(r.Context(), time.Now())
+			vm = vm.withMeta(meta)
+		}
+	}
+
+	body, err := rd.encode(vm, fields)
+	if err != nil {
+		return err
+	}
+
+	if err := rd.validator.check(r, vm, body, rd.shape(), fields != nil); err != nil {
+		return err
+	}
+
+	if etagBody == nil {
+		etagBody = body
+	}
+
+	code := vm.statusCode()
+	if code == 0 {
+		code = http.StatusOK
+	}
+
+	header := w.Header()
+
+	// Envelope headers are applied first so the Renderer's own headers take precedence
+	for key, values := range vm.headers() {
+		header[key] = append([]string(nil), values...)
+	}
+
+	if serverTiming != "" {
+		header.Set("Server-Timing", serverTiming)
+	}
+
+	setWarningHeaders(header, vm.warnings())
+
+	// The coding is chosen before validators since strong ETags differ per coding
+	coding := rd.contentCoding(r, header, body)
+
+	// Validators are only meaningful for successful representations
+	if code >= http.StatusOK && code < http.StatusMultipleChoices {
+		version, lastModified := vm.validators()
+		etag := rd.etag(version, etagBody)
+
+		// Clients may still hold the tag of another coding, which weak comparison accepts
+		notModified := isNotModified(r, etag, lastModified)
+		if coding != "" {
+			etag = encodedETag(etag, coding)
+			notModified = notModified || isNotModified(r, etag, lastModified)
+		}
+
+		if etag != "" {
+			header.Set("ETag", etag)
+		}
+
+		if !lastModified.IsZero() {
+			header.Set("Last-Modified", lastModified.UTC().Format(http.TimeFormat))
+		}
+
+		if notModified {
+			w.WriteHeader(http.StatusNotModified)
+			return nil
+		}
+	}
+
+	if errVM := vm.errorVM(); errVM != nil {
+		// Conflict responses advertise the current version for the next If-Match
+		if errVM.Conflict != nil && errVM.Conflict.CurrentVersion != "" {
+			header.Set("ETag", NewETag(errVM.Conflict.CurrentVersion, false))
+		}
+
+		// Mirror the retry delay unless the handler already set the header
+		if errVM.RetryAfter > 0 && header.Get("Retry-After") == "" {
+			header.Set("Retry-After", strconv.Itoa(errVM.RetryAfter))
+		}
+	}
+
+	if rd.signer != nil {
+		signature, err := rd.signer.Sign(body)
+		if err != nil {
+			return err
+		}
+		header.Set(rd.signer.header, signature)
+	}
+
+	// Signatures cover the JSON itself, while digests cover the content as sent
+	if coding != "" {
+		if body, err = rd.compression.compress(coding, body); err != nil {
+			return err
+		}
+		header.Set("Content-Encoding", coding)
+	}
+
+	if len(rd.digests) > 0 {
+		header.Set("Content-Digest", contentDigest(body, rd.digests))
+	}
+
+	header.Set("Content-Type", "application/json; charset=utf-8")
+	header.Set("Content-Length", strconv.Itoa(len(body)))
+	w.WriteHeader(code)
+
+	// HEAD responses carry headers only
+	if r != nil && r.Method == http.MethodHead {
+		return nil
+	}
+
+	_, err = w.Write(body)
+	return err
+}
+
+// RenderError writes err as a gores error envelope without a data payload.
+// The status code and error details are derived exactly as in ResponseVM.SetErrorFromError,
+// except that the code of multi-error trees is picked by the strategy set via SetStatusStrategy.
+// It is used by the gores middlewares and is convenient in handlers that return early.
+func (rd *Renderer) RenderError(w http.ResponseWriter, r *http.Request, err error) error {
+	return rd.Render(w, r, NewResponseVM[*struct{}]().setErrorFromError(err, rd.strategy))
+}
+
+// encode marshals the envelope, keeps the selected fields of the data, applies the
+// configured schema or bare mode and canonicalizes the result when required.
+func (rd *Renderer) encode(vm Envelope, fields fieldSelection) ([]byte, error) {
+	var body []byte
+	var err error
+	if rd.bare {
+		body, err = rd.encodeBare(vm)
+		if err == nil && fields != nil && vm.errorVM() == nil {
+			body, err = fields.filter(body)
+		}
+	} else if body, err = json.Marshal(vm); err == nil {
+		if fields != nil {
+			body, err = filterData(body, fields)
+		}
+		if err == nil {
+			body, err = rd.schema.Encode(body)
+		}
+	}
+
+	if err != nil || (!rd.canonical && rd.signer == nil) {
+		return body, err
+	}
+
+	return CanonicalJSON(body)
+}
+
+// shape returns the wire shape envelopes are written in.
+func (rd *Renderer) shape() envelopeShape {
+	if rd.bare {
+		return envelopeShape{bare: true, bareErrorKey: rd.bareErrorKey}
+	}
+
+	return envelopeShape{schema: rd.schema}
+}
+
+// filterData keeps the selected fields of the data member of an encoded envelope.
+func filterData(body []byte, fields fieldSelection) ([]byte, error) {
+	members, err := decodeJSONObject(body)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range members {
+		if members[i].key == "data" {
+			if members[i].value, err = fields.filter(members[i].value); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	return members.raw(), nil
+}
+
+// encodeBare marshals only the data of successful responses or the error of failed ones.
+func (rd *Renderer) encodeBare(vm Envelope) ([]byte, error) {
+	if !bareFailure(vm) {
+		return json.Marshal(vm.payload())
+	}
+
+	errVM := vm.errorVM()
+	if errVM == nil {
+		errVM = NewResponseErrorVM().SetMessage(http.StatusText(vm.statusCode()))
+	}
+
+	if rd.bareErrorKey != "" {
+		return json.Marshal(map[string]*ResponseErrorVM{rd.bareErrorKey: errVM})
+	}
+
+	return json.Marshal(errVM)
+}
+
+// bareFailure reports whether bare mode writes the error of the envelope instead of its data.
+// Itemized payloads such as batches carry their errors per item, so their data is always written.
+func bareFailure(vm Envelope) bool {
+	if _, itemized := vm.payload().(itemizedPayload); itemized {
+		return false
+	}
+
+	return vm.errorVM() != nil || vm.statusCode() >= http.StatusBadRequest
+}
+
+// contentCoding returns the coding to compress body with, or "" to send it as is.
+// Bodies below the threshold and bodies already carrying a Content-Encoding are not compressed.
+func (rd *Renderer) contentCoding(r *http.Request, header http.Header, body []byte) string {
+	if rd.compression == nil {
+		return ""
+	}
+
+	addVary(header, "Accept-Encoding")
+
+	if len(body) < rd.compression.threshold || header.Get("Content-Encoding") != "" {
+		return ""
+	}
+
+	return rd.compression.negotiate(r)
+}
+
+// computesETag reports whether the ETag of a successful vm is computed from its body.
+func (rd *Renderer) computesETag(vm Envelope) bool {
+	code := vm.statusCode()
+	version, _ := vm.validators()
+	return rd.etagMode != ETagNone && version == "" && code < http.StatusMultipleChoices &&
+		(code >= http.StatusOK || code == 0)
+}
+
+// etag resolves the entity tag for an encoded body according to the Renderer settings.
+// A caller-supplied version wins over a computed hash.
+func (rd *Renderer) etag(version string, body []byte) string {
+	if version != "" {
+		return NewETag(version, rd.etagMode == ETagWeak)
+	}
+
+	if rd.etagMode == ETagNone {
+		return ""
+	}
+
+	return computeETag(body, rd.etagMode == ETagWeak)
+}
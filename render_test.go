@@ -0,0 +1,210 @@
+package gores
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/fikri240794/gocerr"
+)
+
+func TestRenderer_Render(t *testing.T) {
+	testCases := []struct {
+		Name           string
+		Renderer       *Renderer
+		Method         string
+		Headers        map[string]string
+		Response       *ResponseVM[*someStruct]
+		ExpectedCode   int
+		ExpectedETag   string
+		ExpectedBody   string
+		ExpectETagSent bool
+	}{
+		{
+			Name:         "DefaultCode",
+			Renderer:     NewRenderer(),
+			Method:       http.MethodGet,
+			Response:     NewResponseVM[*someStruct]().SetData(&someStruct{SomeField: "value"}),
+			ExpectedCode: http.StatusOK,
+			ExpectedBody: `{"code":0,"data":{"SomeField":"value"}}`,
+		},
+		{
+			Name:         "CallerSuppliedVersion",
+			Renderer:     NewRenderer(),
+			Method:       http.MethodGet,
+			Response:     NewResponseVM[*someStruct]().SetCode(http.StatusOK).SetVersion("7"),
+			ExpectedCode: http.StatusOK,
+			ExpectedETag: `"7"`,
+			ExpectedBody: `{"code":200}`,
+		},
+		{
+			Name:         "WeakVersion",
+			Renderer:     NewRenderer().SetETagMode(ETagWeak),
+			Method:       http.MethodGet,
+			Response:     NewResponseVM[*someStruct]().SetCode(http.StatusOK).SetVersion("7"),
+			ExpectedCode: http.StatusOK,
+			ExpectedETag: `W/"7"`,
+			ExpectedBody: `{"code":200}`,
+		},
+		{
+			Name:         "IfNoneMatch",
+			Renderer:     NewRenderer(),
+			Method:       http.MethodGet,
+			Headers:      map[string]string{"If-None-Match": `"7"`},
+			Response:     NewResponseVM[*someStruct]().SetCode(http.StatusOK).SetVersion("7"),
+			ExpectedCode: http.StatusNotModified,
+			ExpectedETag: `"7"`,
+		},
+		{
+			Name:         "IfModifiedSince",
+			Renderer:     NewRenderer(),
+			Method:       http.MethodGet,
+			Headers:      map[string]string{"If-Modified-Since": "Tue, 02 Jan 2024 03:04:05 GMT"},
+			Response:     NewResponseVM[*someStruct]().SetCode(http.StatusOK).SetLastModified(time.Date(2024, time.January, 2, 3, 4, 5, 0, time.UTC)),
+			ExpectedCode: http.StatusNotModified,
+		},
+		{
+			Name:         "ErrorResponseHasNoValidators",
+			Renderer:     NewRenderer().SetETagMode(ETagStrong),
+			Method:       http.MethodGet,
+			Headers:      map[string]string{"If-None-Match": "*"},
+			Response:     NewResponseVM[*someStruct]().SetCode(http.StatusNotFound).SetError(NewResponseErrorVM().SetMessage("not found")),
+			ExpectedCode: http.StatusNotFound,
+			ExpectedBody: `{"code":404,"error":{"message":"not found"}}`,
+		},
+		{
+			Name:         "HeadHasNoBody",
+			Renderer:     NewRenderer(),
+			Method:       http.MethodHead,
+			Response:     NewResponseVM[*someStruct]().SetCode(http.StatusOK),
+			ExpectedCode: http.StatusOK,
+		},
+		{
+			Name:           "ComputedStrongETag",
+			Renderer:       NewRenderer().SetETagMode(ETagStrong),
+			Method:         http.MethodGet,
+			Response:       NewResponseVM[*someStruct]().SetCode(http.StatusOK),
+			ExpectedCode:   http.StatusOK,
+			ExpectedBody:   `{"code":200}`,
+			ExpectETagSent: true,
+		},
+	}
+
+	for i := range testCases {
+		t.Run(testCases[i].Name, func(t *testing.T) {
+			r := httptest.NewRequest(testCases[i].Method, "/", nil)
+			for key, value := range testCases[i].Headers {
+				r.Header.Set(key, value)
+			}
+			w := httptest.NewRecorder()
+
+			if err := testCases[i].Renderer.Render(w, r, testCases[i].Response); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if w.Code != testCases[i].ExpectedCode {
+				t.Errorf("expected code is %d, got %d", testCases[i].ExpectedCode, w.Code)
+			}
+
+			if testCases[i].ExpectedETag != "" && w.Header().Get("ETag") != testCases[i].ExpectedETag {
+				t.Errorf("expected etag is %s, got %s", testCases[i].ExpectedETag, w.Header().Get("ETag"))
+			}
+
+			if testCases[i].ExpectETagSent && w.Header().Get("ETag") == "" {
+				t.Error("expected computed etag, got none")
+			}
+
+			if w.Body.String() != testCases[i].ExpectedBody {
+				t.Errorf("expected body is %s, got %s", testCases[i].ExpectedBody, w.Body.String())
+			}
+		})
+	}
+}
+
+// TestRenderer_ComputedETagRoundTrip tests that a computed ETag is honored on the next poll
+func TestRenderer_ComputedETagRoundTrip(t *testing.T) {
+	renderer := NewRenderer().SetETagMode(ETagStrong)
+	response := NewResponseVM[*someStruct]().SetCode(http.StatusOK).SetData(&someStruct{SomeField: "value"})
+
+	first := httptest.NewRecorder()
+	if err := renderer.Render(first, httptest.NewRequest(http.MethodGet, "/", nil), response); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	r := httptest.NewRequest(http.MethodGet, "/", nil)
+	r.Header.Set("If-None-Match", first.Header().Get("ETag"))
+	second := httptest.NewRecorder()
+	if err := renderer.Render(second, r, response); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if second.Code != http.StatusNotModified {
+		t.Errorf("expected code is %d, got %d", http.StatusNotModified, second.Code)
+	}
+
+	if second.Body.Len() != 0 {
+		t.Errorf("expected empty body, got %s", second.Body.String())
+	}
+}
+
+func TestRenderer_BareMode(t *testing.T) {
+	testCases := []struct {
+		Name         string
+		ErrorKey     string
+		Response     *ResponseVM[*someStruct]
+		ExpectedCode int
+		ExpectedBody string
+	}{
+		{
+			Name:         "Success",
+			Response:     NewResponseVM[*someStruct]().SetCode(http.StatusOK).SetData(&someStruct{SomeField: "value"}),
+			ExpectedCode: http.StatusOK,
+			ExpectedBody: `{"SomeField":"value"}`,
+		},
+		{
+			Name:         "SuccessWithoutData",
+			Response:     NewResponseVM[*someStruct]().SetCode(http.StatusAccepted),
+			ExpectedCode: http.StatusAccepted,
+			ExpectedBody: `null`,
+		},
+		{
+			Name:         "Error",
+			Response:     NewResponseVM[*someStruct]().SetErrorFromError(gocerr.New(http.StatusNotFound, "user not found")),
+			ExpectedCode: http.StatusNotFound,
+			ExpectedBody: `{"message":"user not found"}`,
+		},
+		{
+			Name:         "WrappedError",
+			ErrorKey:     "error",
+			Response:     NewResponseVM[*someStruct]().SetErrorFromError(gocerr.New(http.StatusUnprocessableEntity, "invalid", gocerr.NewErrorField("name", "name is required"))),
+			ExpectedCode: http.StatusUnprocessableEntity,
+			ExpectedBody: `{"error":{"message":"invalid","error_fields":[{"field":"name","message":"name is required"}]}}`,
+		},
+	}
+
+	for i := range testCases {
+		t.Run(testCases[i].Name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			renderer := NewRenderer().SetBareMode(true).SetBareErrorKey(testCases[i].ErrorKey)
+			if err := renderer.Render(w, nil, testCases[i].Response); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if w.Code != testCases[i].ExpectedCode {
+				t.Errorf("expected code is %d, got %d", testCases[i].ExpectedCode, w.Code)
+			}
+
+			if w.Body.String() != testCases[i].ExpectedBody {
+				t.Errorf("expected body is %s, got %s", testCases[i].ExpectedBody, w.Body.String())
+			}
+
+			actual, err := DecodeBareResponse[*someStruct](w.Result(), testCases[i].ErrorKey)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			testResponseVMEquality(t, testCases[i].Response, actual)
+		})
+	}
+}
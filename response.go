@@ -4,6 +4,7 @@ package gores
 
 import (
 	"net/http"
+	"time"
 
 	"github.com/fikri240794/gocerr"
 )
@@ -11,10 +12,18 @@ import (
 // ResponseVM represents a standardized HTTP response structure with generic data support.
 // It provides a consistent format for API responses including status codes, error information, and data payload.
 // The generic type T allows for type-safe data handling while maintaining flexibility.
+// Lists and headers are held through pointers so that ResponseVM stays comparable when T is.
 type ResponseVM[T comparable] struct {
-	Code  int              `json:"code"`            // HTTP status code
-	Error *ResponseErrorVM `json:"error,omitempty"` // Error details if any
-	Data  T                `json:"data,omitempty"`  // Response payload data
+	Code     int                   `json:"code"`               // HTTP status code
+	Error    *ResponseErrorVM      `json:"error,omitempty"`    // Error details if any
+	Errors   *[]*ResponseErrorVM   `json:"errors,omitempty"`   // Errors of a partial response if any
+	Data     T                     `json:"data,omitempty"`     // Response payload data
+	Warnings *[]*ResponseWarningVM `json:"warnings,omitempty"` // Non-fatal warnings if any
+	Meta     *MetaVM               `json:"meta,omitempty"`     // Response metadata if any
+
+	version      string       // Caller-supplied resource version used as the ETag
+	lastModified time.Time    // Resource modification time used for Last-Modified
+	header       *http.Header // Additional headers written by the Renderer
 }
 
 // NewResponseVM creates a new instance of ResponseVM with zero values.
@@ -47,12 +56,62 @@ func (vm *ResponseVM[T]) SetError(err *ResponseErrorVM) *ResponseVM[T] {
 	return vm
 }
 
+// SetMeta sets the metadata block for the response.
+// Metadata collected by the Meta middleware is merged into a copy at render time,
+// with values set here taking precedence, so one MetaVM can be shared across requests.
+func (vm *ResponseVM[T]) SetMeta(meta *MetaVM) *ResponseVM[T] {
+	vm.Meta = meta
+	return vm
+}
+
+// SetVersion sets a caller-supplied resource version used as the response ETag.
+// When set, the Renderer uses it instead of hashing the encoded envelope.
+// Both bare versions ("42") and quoted entity tags ("\"42\"", "W/\"42\"") are accepted.
+func (vm *ResponseVM[T]) SetVersion(version string) *ResponseVM[T] {
+	vm.version = version
+	return vm
+}
+
+// SetLastModified sets the modification time of the resource carried in Data.
+// The Renderer emits it as Last-Modified and uses it to evaluate If-Modified-Since.
+func (vm *ResponseVM[T]) SetLastModified(lastModified time.Time) *ResponseVM[T] {
+	vm.lastModified = lastModified
+	return vm
+}
+
+// SetHeader sets an HTTP header that the Renderer writes alongside the envelope.
+// Headers are not part of the JSON body; use them for values such as Location or Retry-After.
+// Setting an empty value removes the header.
+func (vm *ResponseVM[T]) SetHeader(key, value string) *ResponseVM[T] {
+	if vm.header == nil {
+		vm.header = &http.Header{}
+	}
+
+	if value == "" {
+		vm.header.Del(key)
+		return vm
+	}
+
+	vm.header.Set(key, value)
+	return vm
+}
+
 // SetErrorFromError automatically processes a Go error and sets appropriate response fields.
 // It leverages gocerr helper functions for robust error handling and code extraction.
 // For nil errors, the method returns early without modifications for performance.
 // For gocerr.Error types, it extracts the custom HTTP status code and error fields.
 // For standard errors, it defaults to HTTP 500 Internal Server Error.
+// When several gocerr.Error values are joined, the code is picked by StatusMostSevere;
+// Renderer.SetStatusStrategy picks it differently for errors passed to RenderError.
+// Wrapped context.Canceled and context.DeadlineExceeded map to 499 and 504 respectively.
+// Errors with status 429, 503 or 504 and registered transient errors are marked retryable.
 func (vm *ResponseVM[T]) SetErrorFromError(err error) *ResponseVM[T] {
+	return vm.setErrorFromError(err, nil)
+}
+
+// setErrorFromError implements SetErrorFromError, resolving the status code of
+// multi-error trees with strategy, or StatusMostSevere when nil.
+func (vm *ResponseVM[T]) setErrorFromError(err error, strategy StatusStrategy) *ResponseVM[T] {
 	// Early return for nil errors to avoid unnecessary processing
 	if err == nil {
 		return vm
@@ -61,14 +120,85 @@ func (vm *ResponseVM[T]) SetErrorFromError(err error) *ResponseVM[T] {
 	// Default to internal server error for safety
 	vm.Code = http.StatusInternalServerError
 
-	// Use gocerr.GetErrorCode for safe error code extraction
-	if errorCode := gocerr.GetErrorCode(err); errorCode != 0 {
+	// Multi-error trees carrying several custom errors resolve their code via the status strategy
+	if errorCode := statusFromCustomErrors(collectCustomErrors(err), strategy); errorCode != 0 {
+		vm.Code = errorCode
+	} else if errorCode := gocerr.GetErrorCode(err); errorCode != 0 {
 		// Override with custom error code if available
 		vm.Code = errorCode
+	} else if contextCode := contextErrorCode(err); contextCode != 0 {
+		// Canceled and timed out requests are not server failures
+		vm.Code = contextCode
 	}
 
 	// Parse error details efficiently using enhanced ParseError method
 	vm.Error = NewResponseErrorVM().ParseError(err)
 
+	// Flag transient failures so clients know whether retrying is worthwhile
+	vm.Error.applyRetryability(vm.Code, err)
+
 	return vm
 }
+
+// statusCode returns the HTTP status code carried by the envelope.
+func (vm *ResponseVM[T]) statusCode() int {
+	return vm.Code
+}
+
+// errorVM returns the error details carried by the envelope.
+func (vm *ResponseVM[T]) errorVM() *ResponseErrorVM {
+	return vm.Error
+}
+
+// payload returns the data carried by the envelope.
+func (vm *ResponseVM[T]) payload() interface{} {
+	return vm.Data
+}
+
+// withMeta returns a copy of the envelope whose metadata block is merged with collected
+// metadata. The envelope and its MetaVM are left untouched, since handlers may share them.
+func (vm *ResponseVM[T]) withMeta(meta *MetaVM) Envelope {
+	copied := *vm
+	if vm.Meta == nil {
+		copied.Meta = meta
+	} else {
+		copied.Meta = vm.Meta.clone().merge(meta)
+	}
+
+	return &copied
+}
+
+// warnings returns the warnings carried by the envelope.
+func (vm *ResponseVM[T]) warnings() []*ResponseWarningVM {
+	if vm.Warnings == nil {
+		return nil
+	}
+
+	return *vm.Warnings
+}
+
+// withWarnings returns a copy of the envelope that also carries warnings, such as those
+// collected from the request context, leaving the envelope itself untouched.
+func (vm *ResponseVM[T]) withWarnings(warnings []*ResponseWarningVM) Envelope {
+	if len(warnings) == 0 {
+		return vm
+	}
+
+	copied := *vm
+	copied.Warnings = nil
+	return copied.AddWarnings(vm.warnings()...).AddWarnings(warnings...)
+}
+
+// headers returns the additional headers carried by the envelope.
+func (vm *ResponseVM[T]) headers() http.Header {
+	if vm.header == nil {
+		return nil
+	}
+
+	return *vm.header
+}
+
+// validators returns the caller-supplied version and modification time.
+func (vm *ResponseVM[T]) validators() (string, time.Time) {
+	return vm.version, vm.lastModified
+}
@@ -0,0 +1,187 @@
+package gores
+
+import (
+	"fmt"
+	"net/http"
+)
+
+// BatchItemVM represents the outcome of a single item in a batch operation.
+// Each item carries its own status code together with either data or error details,
+// mirroring the shape of ResponseVM so clients can handle items uniformly.
+type BatchItemVM[T comparable] struct {
+	Index int              `json:"index"`           // Position of the item in the original request
+	Code  int              `json:"code"`            // HTTP status code for this item
+	Error *ResponseErrorVM `json:"error,omitempty"` // Error details if the item failed
+	Data  T                `json:"data,omitempty"`  // Item payload data
+}
+
+// BatchSummaryVM contains aggregated counts for a batch operation.
+type BatchSummaryVM struct {
+	Total     int `json:"total"`     // Number of items processed
+	Succeeded int `json:"succeeded"` // Number of items that succeeded
+	Failed    int `json:"failed"`    // Number of items that failed
+}
+
+// BatchResponseVM represents per-item results of a batch operation.
+// It is used as the data payload of a ResponseVM; see ToResponseVM.
+type BatchResponseVM[T comparable] struct {
+	Items   []*BatchItemVM[T] `json:"items"`   // Per-item results in request order
+	Summary BatchSummaryVM    `json:"summary"` // Aggregated counts
+}
+
+// itemizedPayload is implemented by payloads that carry an outcome per item.
+// Bare mode writes them even when the response failed, since the items hold the errors.
+type itemizedPayload interface {
+	itemized()
+}
+
+// NewBatchItemVM creates a new batch item with zero values.
+// The item index is assigned when it is added to a BatchResponseVM.
+func NewBatchItemVM[T comparable]() *BatchItemVM[T] {
+	return &BatchItemVM[T]{}
+}
+
+// SetCode sets the HTTP status code for the item.
+// This method uses method chaining pattern for fluent API design.
+func (vm *BatchItemVM[T]) SetCode(code int) *BatchItemVM[T] {
+	vm.Code = code
+	return vm
+}
+
+// SetData sets the data payload for the item.
+// This method uses method chaining pattern for fluent API design.
+func (vm *BatchItemVM[T]) SetData(data T) *BatchItemVM[T] {
+	vm.Data = data
+	return vm
+}
+
+// SetError sets the error information for the item.
+// This method uses method chaining pattern for fluent API design.
+func (vm *BatchItemVM[T]) SetError(err *ResponseErrorVM) *BatchItemVM[T] {
+	vm.Error = err
+	return vm
+}
+
+// SetErrorFromError processes a Go error exactly like ResponseVM.SetErrorFromError.
+// For nil errors the item is left unchanged.
+func (vm *BatchItemVM[T]) SetErrorFromError(err error) *BatchItemVM[T] {
+	if err == nil {
+		return vm
+	}
+
+	// Reuse the envelope logic so items and responses map errors identically
+	response := NewResponseVM[T]().SetErrorFromError(err)
+	vm.Code = response.Code
+	vm.Error = response.Error
+
+	return vm
+}
+
+// succeeded reports whether the item completed successfully.
+// Items without an explicit code are considered successful unless they carry an error.
+func (vm *BatchItemVM[T]) succeeded() bool {
+	return vm.Error == nil && vm.Code < http.StatusBadRequest
+}
+
+// NewBatchResponseVM creates a new empty batch response.
+// The Items slice is pre-allocated so that empty batches encode as an empty array.
+func NewBatchResponseVM[T comparable]() *BatchResponseVM[T] {
+	return &BatchResponseVM[T]{
+		Items: make([]*BatchItemVM[T], 0),
+	}
+}
+
+// AddItems appends item results in request order and assigns their indexes.
+// Nil items are skipped. The summary is refreshed after every call.
+func (vm *BatchResponseVM[T]) AddItems(items ...*BatchItemVM[T]) *BatchResponseVM[T] {
+	for _, item := range items {
+		if item == nil {
+			continue
+		}
+
+		item.Index = len(vm.Items)
+		vm.Items = append(vm.Items, item)
+	}
+
+	vm.summarize()
+	return vm
+}
+
+// itemized marks the batch as an itemizedPayload.
+func (vm *BatchResponseVM[T]) itemized() {}
+
+// StatusCode computes the overall HTTP status code of the batch.
+// It returns 200 when every item succeeded (or the batch is empty), the shared
+// status code when every item failed with the same 4xx code, and 207 Multi-Status otherwise.
+// A common 5xx code is not surfaced, since clients would treat the whole batch as a server failure.
+func (vm *BatchResponseVM[T]) StatusCode() int {
+	vm.summarize()
+
+	if vm.Summary.Failed == 0 {
+		return http.StatusOK
+	}
+
+	if vm.Summary.Succeeded > 0 {
+		return http.StatusMultiStatus
+	}
+
+	// Every item failed; surface their common code if there is one
+	code := 0
+	for _, item := range vm.Items {
+		if item == nil {
+			continue
+		}
+
+		if code == 0 {
+			code = item.Code
+		} else if item.Code != code {
+			return http.StatusMultiStatus
+		}
+	}
+
+	if code < http.StatusBadRequest || code >= http.StatusInternalServerError {
+		return http.StatusMultiStatus
+	}
+
+	return code
+}
+
+// ToResponseVM wraps the batch in a ResponseVM with the computed overall status code.
+// When every item failed with the same 4xx code the envelope also carries a summary error
+// message; 207 responses never do. In bare mode the items are written either way.
+func (vm *BatchResponseVM[T]) ToResponseVM() *ResponseVM[*BatchResponseVM[T]] {
+	code := vm.StatusCode()
+	response := NewResponseVM[*BatchResponseVM[T]]().
+		SetCode(code).
+		SetData(vm)
+
+	if code >= http.StatusBadRequest {
+		response.SetError(
+			NewResponseErrorVM().
+				SetMessage(fmt.Sprintf("all %d batch items failed", vm.Summary.Total)),
+		)
+	}
+
+	return response
+}
+
+// summarize recalculates the summary counts from the current items.
+// Nil items, which AddItems skips but Items may still hold, are not counted.
+func (vm *BatchResponseVM[T]) summarize() {
+	summary := BatchSummaryVM{}
+
+	for _, item := range vm.Items {
+		if item == nil {
+			continue
+		}
+
+		summary.Total++
+		if item.succeeded() {
+			summary.Succeeded++
+		} else {
+			summary.Failed++
+		}
+	}
+
+	vm.Summary = summary
+}
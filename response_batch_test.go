@@ -0,0 +1,144 @@
+package gores
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/fikri240794/gocerr"
+)
+
+func TestBatchItemVM_SetErrorFromError(t *testing.T) {
+	testCases := []struct {
+		Name         string
+		Err          error
+		ExpectedCode int
+		ExpectError  bool
+	}{
+		{Name: "Nil", Err: nil},
+		{Name: "StandardError", Err: errors.New("boom"), ExpectedCode: http.StatusInternalServerError, ExpectError: true},
+		{
+			Name:         "CustomErrorWithFields",
+			Err:          gocerr.New(http.StatusUnprocessableEntity, "invalid", gocerr.NewErrorField("email", "email is required")),
+			ExpectedCode: http.StatusUnprocessableEntity,
+			ExpectError:  true,
+		},
+	}
+
+	for i := range testCases {
+		t.Run(testCases[i].Name, func(t *testing.T) {
+			item := NewBatchItemVM[*someStruct]().SetErrorFromError(testCases[i].Err)
+			expected := NewResponseVM[*someStruct]().SetErrorFromError(testCases[i].Err)
+
+			if item.Code != testCases[i].ExpectedCode {
+				t.Errorf("expected code is %d, got %d", testCases[i].ExpectedCode, item.Code)
+			}
+
+			if (item.Error != nil) != testCases[i].ExpectError {
+				t.Errorf("expected error presence is %t, got %t", testCases[i].ExpectError, item.Error != nil)
+			}
+
+			testResponseErrorVMEquality(t, expected.Error, item.Error)
+		})
+	}
+}
+
+func TestBatchResponseVM_StatusCode(t *testing.T) {
+	ok := func() *BatchItemVM[*someStruct] {
+		return NewBatchItemVM[*someStruct]().SetCode(http.StatusCreated).SetData(&someStruct{SomeField: "ok"})
+	}
+	failed := func(code int) *BatchItemVM[*someStruct] {
+		return NewBatchItemVM[*someStruct]().SetErrorFromError(gocerr.New(code, "failed"))
+	}
+
+	testCases := []struct {
+		Name              string
+		Items             []*BatchItemVM[*someStruct]
+		ExpectedCode      int
+		ExpectedSucceeded int
+		ExpectedFailed    int
+		ExpectError       bool
+	}{
+		{Name: "Empty", ExpectedCode: http.StatusOK},
+		{Name: "AllSucceeded", Items: []*BatchItemVM[*someStruct]{ok(), ok()}, ExpectedCode: http.StatusOK, ExpectedSucceeded: 2},
+		{Name: "Mixed", Items: []*BatchItemVM[*someStruct]{ok(), failed(http.StatusConflict)}, ExpectedCode: http.StatusMultiStatus, ExpectedSucceeded: 1, ExpectedFailed: 1},
+		{Name: "AllFailedCommonCode", Items: []*BatchItemVM[*someStruct]{failed(http.StatusConflict), failed(http.StatusConflict)}, ExpectedCode: http.StatusConflict, ExpectedFailed: 2, ExpectError: true},
+		{Name: "AllFailedCommonServerError", Items: []*BatchItemVM[*someStruct]{failed(http.StatusServiceUnavailable), failed(http.StatusServiceUnavailable)}, ExpectedCode: http.StatusMultiStatus, ExpectedFailed: 2},
+		{Name: "AllFailedDifferentCodes", Items: []*BatchItemVM[*someStruct]{failed(http.StatusConflict), failed(http.StatusBadRequest)}, ExpectedCode: http.StatusMultiStatus, ExpectedFailed: 2},
+	}
+
+	for i := range testCases {
+		t.Run(testCases[i].Name, func(t *testing.T) {
+			batch := NewBatchResponseVM[*someStruct]().AddItems(testCases[i].Items...)
+			response := batch.ToResponseVM()
+
+			if response.Code != testCases[i].ExpectedCode {
+				t.Errorf("expected code is %d, got %d", testCases[i].ExpectedCode, response.Code)
+			}
+
+			if response.Data.Summary.Total != len(testCases[i].Items) {
+				t.Errorf("expected total is %d, got %d", len(testCases[i].Items), response.Data.Summary.Total)
+			}
+
+			if response.Data.Summary.Succeeded != testCases[i].ExpectedSucceeded {
+				t.Errorf("expected succeeded is %d, got %d", testCases[i].ExpectedSucceeded, response.Data.Summary.Succeeded)
+			}
+
+			if response.Data.Summary.Failed != testCases[i].ExpectedFailed {
+				t.Errorf("expected failed is %d, got %d", testCases[i].ExpectedFailed, response.Data.Summary.Failed)
+			}
+
+			if (response.Error != nil) != testCases[i].ExpectError {
+				t.Errorf("expected error presence is %t, got %t", testCases[i].ExpectError, response.Error != nil)
+			}
+
+			for index, item := range response.Data.Items {
+				if item.Index != index {
+					t.Errorf("expected index is %d, got %d", index, item.Index)
+				}
+			}
+		})
+	}
+}
+
+func TestBatchResponseVM_NilItems(t *testing.T) {
+	batch := NewBatchResponseVM[*someStruct]()
+	batch.Items = append(batch.Items, nil, NewBatchItemVM[*someStruct]().SetErrorFromError(gocerr.New(http.StatusConflict, "failed")), nil)
+
+	response := batch.ToResponseVM()
+
+	if response.Code != http.StatusConflict {
+		t.Errorf("expected code is %d, got %d", http.StatusConflict, response.Code)
+	}
+
+	if batch.Summary.Total != 1 || batch.Summary.Failed != 1 {
+		t.Errorf("expected nil items not to be counted, got %+v", batch.Summary)
+	}
+}
+
+func TestBatchResponseVM_BareMode(t *testing.T) {
+	batch := NewBatchResponseVM[*someStruct]().AddItems(
+		NewBatchItemVM[*someStruct]().SetErrorFromError(gocerr.New(http.StatusConflict, "first failed")),
+		NewBatchItemVM[*someStruct]().SetErrorFromError(gocerr.New(http.StatusConflict, "second failed")),
+	)
+
+	w := httptest.NewRecorder()
+	if err := NewRenderer().SetBareMode(true).Render(w, nil, batch.ToResponseVM()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if w.Code != http.StatusConflict {
+		t.Errorf("expected code is %d, got %d", http.StatusConflict, w.Code)
+	}
+
+	var body BatchResponseVM[*someStruct]
+	if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(body.Items) != 2 || body.Items[1].Error == nil || body.Items[1].Error.Message != "second failed" {
+		t.Errorf("expected the items to be written, got %s", w.Body.String())
+	}
+}
@@ -0,0 +1,66 @@
+package gores
+
+import (
+	"fmt"
+	"net/http"
+)
+
+// ResponseConflictVM describes the server-side state that a rejected write conflicted with.
+// It lets clients resolve optimistic concurrency conflicts without refetching the resource.
+// All fields are optional and omitted from JSON when empty.
+type ResponseConflictVM struct {
+	CurrentVersion    string      `json:"current_version,omitempty"`    // Current ETag of the resource
+	Current           interface{} `json:"current,omitempty"`            // Current resource representation
+	ConflictingFields []string    `json:"conflicting_fields,omitempty"` // Fields changed concurrently
+}
+
+// NewResponseConflictVM creates a new conflict description for the given current version.
+// The version is normalized to an entity tag so it can be sent back verbatim in If-Match.
+func NewResponseConflictVM(currentVersion string) *ResponseConflictVM {
+	return &ResponseConflictVM{
+		CurrentVersion: NewETag(currentVersion, false),
+	}
+}
+
+// SetCurrent sets the current representation of the resource.
+// This method follows the fluent API pattern for method chaining.
+func (vm *ResponseConflictVM) SetCurrent(current interface{}) *ResponseConflictVM {
+	vm.Current = current
+	return vm
+}
+
+// AddConflictingFields appends the names of fields that were modified concurrently.
+// This method follows the fluent API pattern for method chaining.
+func (vm *ResponseConflictVM) AddConflictingFields(fields ...string) *ResponseConflictVM {
+	vm.ConflictingFields = append(vm.ConflictingFields, fields...)
+	return vm
+}
+
+// SetConflictError sets a concurrency conflict error on the response.
+// The code must be HTTP 409 Conflict or HTTP 412 Precondition Failed; any other value panics,
+// since it is a programming error. The Renderer mirrors the current version in the ETag header.
+func (vm *ResponseVM[T]) SetConflictError(code int, message string, conflict *ResponseConflictVM) *ResponseVM[T] {
+	if code != http.StatusConflict && code != http.StatusPreconditionFailed {
+		panic(fmt.Sprintf("gores: conflict code must be %d or %d, got %d", http.StatusConflict, http.StatusPreconditionFailed, code))
+	}
+
+	vm.Code = code
+	vm.Error = NewResponseErrorVM().
+		SetMessage(message).
+		SetConflict(conflict)
+
+	return vm
+}
+
+// SetPreconditionRequiredError sets an HTTP 428 Precondition Required error on the response.
+// Use it for writes that must carry If-Match but arrived without it; see RequirePreconditions.
+func (vm *ResponseVM[T]) SetPreconditionRequiredError(message string) *ResponseVM[T] {
+	if message == "" {
+		message = preconditionRequiredMessage
+	}
+
+	vm.Code = http.StatusPreconditionRequired
+	vm.Error = NewResponseErrorVM().SetMessage(message)
+
+	return vm
+}
@@ -0,0 +1,142 @@
+package gores
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/fikri240794/gocerr"
+)
+
+func TestResponseVM_SetConflictError(t *testing.T) {
+	testCases := []struct {
+		Name         string
+		Code         int
+		ExpectedCode int
+	}{
+		{Name: "Conflict", Code: http.StatusConflict, ExpectedCode: http.StatusConflict},
+		{Name: "PreconditionFailed", Code: http.StatusPreconditionFailed, ExpectedCode: http.StatusPreconditionFailed},
+	}
+
+	for i := range testCases {
+		t.Run(testCases[i].Name, func(t *testing.T) {
+			conflict := NewResponseConflictVM("v2").
+				SetCurrent(&someStruct{SomeField: "current"}).
+				AddConflictingFields("name", "email")
+
+			vm := NewResponseVM[*someStruct]().
+				SetConflictError(testCases[i].Code, "record was modified by another user", conflict)
+
+			if vm.Code != testCases[i].ExpectedCode {
+				t.Errorf("expected code is %d, got %d", testCases[i].ExpectedCode, vm.Code)
+			}
+
+			if vm.Error == nil || vm.Error.Conflict != conflict {
+				t.Fatal("expected conflict to be attached to the error")
+			}
+
+			if vm.Error.Message != "record was modified by another user" {
+				t.Errorf("expected message is %s, got %s", "record was modified by another user", vm.Error.Message)
+			}
+
+			if vm.Error.Conflict.CurrentVersion != `"v2"` {
+				t.Errorf("expected current version is %s, got %s", `"v2"`, vm.Error.Conflict.CurrentVersion)
+			}
+
+			if len(vm.Error.Conflict.ConflictingFields) != 2 {
+				t.Errorf("expected length of conflicting fields is 2, got %d", len(vm.Error.Conflict.ConflictingFields))
+			}
+		})
+	}
+}
+
+// TestResponseVM_SetConflictError_RejectsOtherCodes tests that codes other than 409 and 412 panic
+func TestResponseVM_SetConflictError_RejectsOtherCodes(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("expected a panic for a non-conflict code")
+		}
+	}()
+
+	NewResponseVM[*someStruct]().SetConflictError(http.StatusBadRequest, "conflict", NewResponseConflictVM("v2"))
+}
+
+// TestResponseVM_SetConflictError_Render tests the JSON shape and ETag header of a conflict response
+func TestResponseVM_SetConflictError_Render(t *testing.T) {
+	vm := NewResponseVM[*someStruct]().
+		SetConflictError(http.StatusConflict, "conflict", NewResponseConflictVM("v2").AddConflictingFields("name"))
+
+	w := httptest.NewRecorder()
+	if err := NewRenderer().Render(w, httptest.NewRequest(http.MethodPut, "/", nil), vm); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if w.Header().Get("ETag") != `"v2"` {
+		t.Errorf("expected etag is %s, got %s", `"v2"`, w.Header().Get("ETag"))
+	}
+
+	expected := `{"code":409,"error":{"message":"conflict","conflict":{"current_version":"\"v2\"","conflicting_fields":["name"]}}}`
+	if w.Body.String() != expected {
+		t.Errorf("expected body is %s, got %s", expected, w.Body.String())
+	}
+
+	var decoded ResponseVM[*someStruct]
+	if err := json.Unmarshal(w.Body.Bytes(), &decoded); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if decoded.Error.Conflict == nil || decoded.Error.Conflict.CurrentVersion != `"v2"` {
+		t.Error("expected conflict to survive a JSON round-trip")
+	}
+
+	// Versions assigned directly are quoted like those passed to NewResponseConflictVM
+	unquoted := NewResponseVM[*someStruct]().
+		SetConflictError(http.StatusConflict, "conflict", &ResponseConflictVM{CurrentVersion: "v3"})
+
+	w = httptest.NewRecorder()
+	if err := NewRenderer().Render(w, httptest.NewRequest(http.MethodPut, "/", nil), unquoted); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if w.Header().Get("ETag") != `"v3"` {
+		t.Errorf("expected etag is %s, got %s", `"v3"`, w.Header().Get("ETag"))
+	}
+}
+
+func TestResponseVM_SetPreconditionRequiredError(t *testing.T) {
+	vm := NewResponseVM[*someStruct]().SetPreconditionRequiredError("")
+
+	if vm.Code != http.StatusPreconditionRequired {
+		t.Errorf("expected code is %d, got %d", http.StatusPreconditionRequired, vm.Code)
+	}
+
+	if vm.Error == nil || vm.Error.Message != preconditionRequiredMessage {
+		t.Error("expected default precondition required message")
+	}
+}
+
+func TestRequirePreconditions(t *testing.T) {
+	testCases := []struct {
+		Name         string
+		Headers      map[string]string
+		ExpectedCode int
+	}{
+		{Name: "Missing", ExpectedCode: http.StatusPreconditionRequired},
+		{Name: "IfMatch", Headers: map[string]string{"If-Match": `"v1"`}},
+		{Name: "IfUnmodifiedSince", Headers: map[string]string{"If-Unmodified-Since": "Tue, 02 Jan 2024 03:04:05 GMT"}},
+	}
+
+	for i := range testCases {
+		t.Run(testCases[i].Name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodPatch, "/", nil)
+			for key, value := range testCases[i].Headers {
+				r.Header.Set(key, value)
+			}
+
+			if code := gocerr.GetErrorCode(RequirePreconditions(r)); code != testCases[i].ExpectedCode {
+				t.Errorf("expected code is %d, got %d", testCases[i].ExpectedCode, code)
+			}
+		})
+	}
+}
@@ -1,13 +1,23 @@
 package gores
 
-import "github.com/fikri240794/gocerr"
+import (
+	"errors"
+	"time"
+
+	"github.com/fikri240794/gocerr"
+)
 
 // ResponseErrorVM represents error information in standardized API responses.
 // It contains a human-readable error message and optional field-specific errors.
 // This structure provides detailed error context for client applications.
 type ResponseErrorVM struct {
 	Message     string                  `json:"message"`                // Primary error message
+	ErrorCode   string                  `json:"error_code,omitempty"`   // Machine-readable error code
+	Path        string                  `json:"path,omitempty"`         // Path of the data affected in a partial response
+	Retryable   bool                    `json:"retryable,omitempty"`    // Whether retrying may succeed
+	RetryAfter  int                     `json:"retry_after,omitempty"`  // Suggested retry delay in seconds
 	ErrorFields []*ResponseErrorFieldVM `json:"error_fields,omitempty"` // Field-specific validation errors
+	Conflict    *ResponseConflictVM     `json:"conflict,omitempty"`     // Current state for concurrency conflicts
 }
 
 // NewResponseErrorVM creates a new instance of ResponseErrorVM with initialized empty fields.
@@ -26,6 +36,36 @@ func (vm *ResponseErrorVM) SetMessage(message string) *ResponseErrorVM {
 	return vm
 }
 
+// SetErrorCode sets a machine-readable error code such as ErrorCodeQuotaExceeded.
+// Unlike the HTTP status code it identifies the specific failure, letting clients
+// branch on it without parsing the message.
+func (vm *ResponseErrorVM) SetErrorCode(errorCode string) *ResponseErrorVM {
+	vm.ErrorCode = errorCode
+	return vm
+}
+
+// SetPath sets the path of the data affected by the error, e.g. "orders" or "profile.avatar".
+// It is used for errors of partial responses; see ResponseVM.SetPartialErrors.
+func (vm *ResponseErrorVM) SetPath(path string) *ResponseErrorVM {
+	vm.Path = path
+	return vm
+}
+
+// SetRetryable marks whether the client may retry the failed request.
+// This method follows the fluent API pattern for method chaining.
+func (vm *ResponseErrorVM) SetRetryable(retryable bool) *ResponseErrorVM {
+	vm.Retryable = retryable
+	return vm
+}
+
+// SetRetryAfter sets the suggested retry delay, rounded up to whole seconds,
+// and marks the error as retryable. The Renderer mirrors it in the Retry-After header.
+func (vm *ResponseErrorVM) SetRetryAfter(retryAfter time.Duration) *ResponseErrorVM {
+	vm.RetryAfter = ceilSeconds(retryAfter)
+	vm.Retryable = true
+	return vm
+}
+
 // AddErrorFields appends one or more field-specific errors to the error response.
 // This method is optimized for performance by pre-calculating required capacity
 // to minimize slice reallocations when adding multiple fields.
@@ -48,9 +88,55 @@ func (vm *ResponseErrorVM) AddErrorFields(errorFields ...*ResponseErrorFieldVM)
 	return vm
 }
 
+// SetConflict attaches the current server-side state of a conflicting resource.
+// This method follows the fluent API pattern for method chaining.
+func (vm *ResponseErrorVM) SetConflict(conflict *ResponseConflictVM) *ResponseErrorVM {
+	vm.Conflict = conflict
+	return vm
+}
+
+// Error returns the error message, so that a ResponseErrorVM received from a downstream
+// service can be returned up the stack as an error and recovered with errors.As.
+func (vm *ResponseErrorVM) Error() string {
+	return vm.Message
+}
+
+// ToGocerr converts the error response back into a gocerr.Error with the given status code.
+// The message and field errors are carried over unchanged, so parsing the result with
+// ParseError yields the same message and error fields. Field locations, codes and
+// rejected values have no gocerr counterpart; return the ResponseErrorVM itself to keep them.
+func (vm *ResponseErrorVM) ToGocerr(code int) gocerr.Error {
+	errorFields := make([]gocerr.ErrorField, 0, len(vm.ErrorFields))
+	for _, errorField := range vm.ErrorFields {
+		if errorField != nil {
+			errorFields = append(errorFields, gocerr.NewErrorField(errorField.Field, errorField.Message))
+		}
+	}
+
+	return gocerr.New(code, vm.Message, errorFields...)
+}
+
+// mapFromResponseError copies every member of a ResponseErrorVM returned as an error,
+// including those a gocerr.Error cannot carry such as the error code and conflict.
+func (vm *ResponseErrorVM) mapFromResponseError(responseErr *ResponseErrorVM) *ResponseErrorVM {
+	*vm = *responseErr
+
+	vm.ErrorFields = make([]*ResponseErrorFieldVM, 0, len(responseErr.ErrorFields))
+	for _, errorField := range responseErr.ErrorFields {
+		if errorField != nil {
+			copied := *errorField
+			vm.ErrorFields = append(vm.ErrorFields, &copied)
+		}
+	}
+
+	return vm
+}
+
 // mapFromCustomError efficiently extracts error information from gocerr.Error types.
 // This method uses gocerr helper functions for safer and more maintainable error field extraction.
 // It optimizes performance by leveraging gocerr's optimized field access methods.
+// A gocerr.ErrorField only has a field and message; location, code and rejected value
+// come from ResponseErrorFieldVM errors joined with it, see mapFromCustomErrors.
 func (vm *ResponseErrorVM) mapFromCustomError(customErr gocerr.Error) *ResponseErrorVM {
 	vm.Message = customErr.Message
 
@@ -77,6 +163,7 @@ func (vm *ResponseErrorVM) mapFromCustomError(customErr gocerr.Error) *ResponseE
 // ParseError automatically extracts error information from any Go error type.
 // It leverages gocerr.Parse for robust error type detection and processing.
 // This method provides the main error parsing logic used throughout the library.
+// Errors joined with errors.Join have their messages and field errors merged.
 // For nil errors, it returns early to avoid unnecessary processing.
 func (vm *ResponseErrorVM) ParseError(err error) *ResponseErrorVM {
 	// Early return for nil errors to optimize performance
@@ -84,7 +171,25 @@ func (vm *ResponseErrorVM) ParseError(err error) *ResponseErrorVM {
 		return vm
 	}
 
-	// Use gocerr.Parse for robust custom error detection and extraction
+	customErrors := collectCustomErrors(err)
+
+	// A lone ResponseErrorVM is copied as-is so that round trips are lossless
+	if len(customErrors) == 1 && customErrors[0].response {
+		return vm.mapFromResponseError(customErrors[0].errorVM)
+	}
+
+	// Errors only reachable through errors.As are not part of the walked tree
+	var responseErr *ResponseErrorVM
+	if len(customErrors) == 0 && errors.As(err, &responseErr) && responseErr != nil {
+		return vm.mapFromResponseError(responseErr)
+	}
+
+	// Walk multi-error trees such as errors.Join and merge every custom error found
+	if len(customErrors) > 0 {
+		return vm.mapFromCustomErrors(customErrors)
+	}
+
+	// Fall back to gocerr.Parse for custom errors only reachable through errors.As
 	if customError, ok := gocerr.Parse(err); ok {
 		return vm.mapFromCustomError(customError)
 	}
@@ -1,11 +1,34 @@
 package gores
 
+import (
+	"encoding/json"
+	"strings"
+	"sync"
+)
+
+// FieldLocation identifies the part of the request a field error refers to.
+type FieldLocation string
+
+const (
+	LocationBody   FieldLocation = "body"   // JSON or form body
+	LocationQuery  FieldLocation = "query"  // URL query parameter
+	LocationPath   FieldLocation = "path"   // URL path parameter
+	LocationHeader FieldLocation = "header" // HTTP request header
+	LocationCookie FieldLocation = "cookie" // HTTP cookie
+)
+
+// RedactedValue replaces rejected values of sensitive fields.
+const RedactedValue = "[REDACTED]"
+
 // ResponseErrorFieldVM represents a field-specific error in API validation responses.
 // It provides detailed information about which field caused an error and why.
 // This structure is commonly used for form validation and request parameter errors.
 type ResponseErrorFieldVM struct {
-	Field   string `json:"field"`   // The name of the field that caused the error
-	Message string `json:"message"` // Human-readable error message for this field
+	Field         string        `json:"field"`                    // The name of the field that caused the error
+	Message       string        `json:"message"`                  // Human-readable error message for this field
+	Location      FieldLocation `json:"location,omitempty"`       // Part of the request holding the field
+	Code          string        `json:"code,omitempty"`           // Machine-readable validation rule that failed
+	RejectedValue interface{}   `json:"rejected_value,omitempty"` // Value that failed validation
 }
 
 // NewResponseErrorFieldVM creates a new field error with the specified field name and message.
@@ -17,3 +40,112 @@ func NewResponseErrorFieldVM(field, message string) *ResponseErrorFieldVM {
 		Message: message,
 	}
 }
+
+// SetLocation sets the part of the request holding the field, e.g. LocationQuery.
+// This method follows the fluent API pattern for method chaining.
+func (vm *ResponseErrorFieldVM) SetLocation(location FieldLocation) *ResponseErrorFieldVM {
+	vm.Location = location
+	return vm
+}
+
+// SetCode sets a machine-readable code for the failed rule, e.g. "required" or "max_length".
+// This method follows the fluent API pattern for method chaining.
+func (vm *ResponseErrorFieldVM) SetCode(code string) *ResponseErrorFieldVM {
+	vm.Code = code
+	return vm
+}
+
+// SetRejectedValue sets the value that failed validation so clients can highlight it.
+// Values of sensitive fields, see RegisterSensitiveFields, are replaced by RedactedValue.
+func (vm *ResponseErrorFieldVM) SetRejectedValue(value interface{}) *ResponseErrorFieldVM {
+	if isSensitiveField(vm.Field) {
+		value = RedactedValue
+	}
+
+	vm.RejectedValue = value
+	return vm
+}
+
+// MarshalJSON encodes the field error, redacting the rejected value of sensitive fields
+// even when it was assigned directly or the field was renamed after SetRejectedValue.
+func (vm ResponseErrorFieldVM) MarshalJSON() ([]byte, error) {
+	type plain ResponseErrorFieldVM
+	if vm.RejectedValue != nil && isSensitiveField(vm.Field) {
+		vm.RejectedValue = RedactedValue
+	}
+
+	return json.Marshal(plain(vm))
+}
+
+// Error formats the field error as "field: message". Joining it with a gocerr.Error, e.g.
+// errors.Join(gocerr.New(422, "invalid input"), fieldErr), lets ParseError keep the location,
+// code and rejected value, which a gocerr.ErrorField cannot carry.
+func (vm *ResponseErrorFieldVM) Error() string {
+	return vm.Field + ": " + vm.Message
+}
+
+// enrich fills in the location, code and rejected value that other carries and vm lacks.
+// It reports false and leaves vm unchanged when other describes a different field error.
+func (vm *ResponseErrorFieldVM) enrich(other *ResponseErrorFieldVM) bool {
+	if vm.Field != other.Field || vm.Message != other.Message ||
+		(vm.Location != "" && other.Location != "" && vm.Location != other.Location) ||
+		(vm.Code != "" && other.Code != "" && vm.Code != other.Code) {
+		return false
+	}
+
+	if vm.Location == "" {
+		vm.Location = other.Location
+	}
+	if vm.Code == "" {
+		vm.Code = other.Code
+	}
+	if vm.RejectedValue == nil {
+		vm.RejectedValue = other.RejectedValue
+	}
+
+	return true
+}
+
+// sensitiveFields holds the normalized names registered via RegisterSensitiveFields.
+var sensitiveFields = struct {
+	mu    sync.RWMutex
+	names []string
+}{
+	names: []string{"password", "passwd", "secret", "token", "apikey", "authorization", "cookie", "creditcard", "cardnumber", "cvv"},
+}
+
+// RegisterSensitiveFields adds names of fields whose rejected values must never be echoed.
+// Names match case-insensitively anywhere in the field path, ignoring "_" and "-", so
+// registering "token" also covers "access_token" and "user.refreshToken".
+// Passwords, secrets, tokens, API keys and card data are registered by default.
+func RegisterSensitiveFields(names ...string) {
+	sensitiveFields.mu.Lock()
+	defer sensitiveFields.mu.Unlock()
+
+	for _, name := range names {
+		if name = normalizeFieldName(name); name != "" {
+			sensitiveFields.names = append(sensitiveFields.names, name)
+		}
+	}
+}
+
+// isSensitiveField reports whether the rejected value of field must be redacted.
+func isSensitiveField(field string) bool {
+	field = normalizeFieldName(field)
+
+	sensitiveFields.mu.RLock()
+	defer sensitiveFields.mu.RUnlock()
+
+	for _, name := range sensitiveFields.names {
+		if strings.Contains(field, name) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// normalizeFieldName lowercases name and drops "_" and "-" for matching.
+func normalizeFieldName(name string) string {
+	return strings.NewReplacer("_", "", "-", "").Replace(strings.ToLower(name))
+}
@@ -1,6 +1,10 @@
 package gores
 
 import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
 	"testing"
 )
 
@@ -108,3 +112,99 @@ func TestResponseErrorFieldVM_StructureValidation(t *testing.T) {
 		t.Error("Message should be modifiable")
 	}
 }
+
+func TestResponseErrorFieldVM_SetRejectedValue(t *testing.T) {
+	RegisterSensitiveFields("ssn")
+
+	testCases := []struct {
+		name     string
+		field    string
+		value    interface{}
+		expected interface{}
+	}{
+		{name: "RegularField", field: "age", value: -1, expected: -1},
+		{name: "Password", field: "password", value: "hunter2", expected: RedactedValue},
+		{name: "NestedToken", field: "credentials.refreshToken", value: "abc", expected: RedactedValue},
+		{name: "SnakeCaseAPIKey", field: "api_key", value: "abc", expected: RedactedValue},
+		{name: "RegisteredField", field: "user.SSN", value: "123-45-6789", expected: RedactedValue},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			vm := NewResponseErrorFieldVM(tc.field, "invalid").SetRejectedValue(tc.value)
+
+			if vm.RejectedValue != tc.expected {
+				t.Errorf("Expected rejected value %v, got %v", tc.expected, vm.RejectedValue)
+			}
+		})
+	}
+}
+
+func TestResponseErrorFieldVM_JSONRoundTrip(t *testing.T) {
+	vm := NewResponseErrorFieldVM("limit", "must be at most 100").
+		SetLocation(LocationQuery).
+		SetCode("max").
+		SetRejectedValue(500)
+
+	data, err := json.Marshal(vm)
+	if err != nil {
+		t.Fatalf("Failed to marshal: %v", err)
+	}
+
+	expected := `{"field":"limit","message":"must be at most 100","location":"query","code":"max","rejected_value":500}`
+	if string(data) != expected {
+		t.Errorf("Expected JSON %s, got %s", expected, string(data))
+	}
+
+	var decoded ResponseErrorFieldVM
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatalf("Failed to unmarshal: %v", err)
+	}
+
+	if decoded.Location != LocationQuery || decoded.Code != "max" || decoded.RejectedValue != float64(500) {
+		t.Errorf("Expected decoded field to keep location, code and rejected value, got %+v", decoded)
+	}
+
+	minimal, _ := json.Marshal(NewResponseErrorFieldVM("name", "required"))
+	if string(minimal) != `{"field":"name","message":"required"}` {
+		t.Errorf("Expected optional members to be omitted, got %s", string(minimal))
+	}
+}
+
+func TestResponseErrorFieldVM_MarshalJSON_Redacts(t *testing.T) {
+	renamed := NewResponseErrorFieldVM("name", "invalid").SetRejectedValue("hunter2")
+	renamed.Field = "password"
+
+	testCases := []struct {
+		name     string
+		vm       *ResponseErrorFieldVM
+		expected string
+	}{
+		{name: "AssignedDirectly", vm: &ResponseErrorFieldVM{Field: "api_key", Message: "invalid", RejectedValue: "abc"}, expected: `{"field":"api_key","message":"invalid","rejected_value":"[REDACTED]"}`},
+		{name: "RenamedAfterSet", vm: renamed, expected: `{"field":"password","message":"invalid","rejected_value":"[REDACTED]"}`},
+		{name: "RegularField", vm: &ResponseErrorFieldVM{Field: "age", Message: "invalid", RejectedValue: -1}, expected: `{"field":"age","message":"invalid","rejected_value":-1}`},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			data, err := json.Marshal(tc.vm)
+			if err != nil {
+				t.Fatalf("Failed to marshal: %v", err)
+			}
+
+			if string(data) != tc.expected {
+				t.Errorf("Expected JSON %s, got %s", tc.expected, string(data))
+			}
+		})
+	}
+
+	// Rendering through a schema redacts as well
+	w := httptest.NewRecorder()
+	NewRenderer().SetSchema(NewEnvelopeSchema().SetKeyCase(CamelCase)).Render(w, nil, NewResponseVM[*someStruct]().
+		SetCode(http.StatusBadRequest).
+		SetError(NewResponseErrorVM().SetMessage("invalid").AddErrorFields(&ResponseErrorFieldVM{Field: "token", Message: "invalid", RejectedValue: "abc"})))
+
+	if !strings.Contains(w.Body.String(), `"rejectedValue":"[REDACTED]"`) {
+		t.Errorf("Expected rendered value to be redacted, got %s", w.Body.String())
+	}
+}
@@ -2,7 +2,9 @@ package gores
 
 import (
 	"errors"
+	"fmt"
 	"net/http"
+	"reflect"
 	"testing"
 
 	"github.com/fikri240794/gocerr"
@@ -65,6 +67,18 @@ func testResponseErrorVMEquality(t *testing.T, expected, actual *ResponseErrorVM
 		if expectedField.Message != actualField.Message {
 			t.Errorf("expected error fields item message at index %d is %s, got %s", i, expectedField.Message, actualField.Message)
 		}
+
+		if expectedField.Location != actualField.Location {
+			t.Errorf("expected error fields item location at index %d is %s, got %s", i, expectedField.Location, actualField.Location)
+		}
+
+		if expectedField.Code != actualField.Code {
+			t.Errorf("expected error fields item code at index %d is %s, got %s", i, expectedField.Code, actualField.Code)
+		}
+
+		if !reflect.DeepEqual(expectedField.RejectedValue, actualField.RejectedValue) {
+			t.Errorf("expected error fields item rejected value at index %d is %v, got %v", i, expectedField.RejectedValue, actualField.RejectedValue)
+		}
 	}
 }
 
@@ -306,3 +320,172 @@ func TestResponseErrorVM_SliceInitialization(t *testing.T) {
 		t.Errorf("After adding one field, length should be 1, got %d", len(vm.ErrorFields))
 	}
 }
+
+// TestResponseErrorVM_AsError tests that ResponseErrorVM can travel up the stack as an error
+func TestResponseErrorVM_AsError(t *testing.T) {
+	downstream := NewResponseErrorVM().
+		SetMessage("validation failed").
+		SetErrorCode("INVALID_ORDER").
+		SetConflict(NewResponseConflictVM("7")).
+		AddErrorFields(NewResponseErrorFieldVM("quantity", "must be positive"))
+
+	err := fmt.Errorf("calling orders service: %w", downstream)
+
+	var target *ResponseErrorVM
+	if !errors.As(err, &target) || target != downstream {
+		t.Fatal("errors.As should find the wrapped ResponseErrorVM")
+	}
+
+	if downstream.Error() != "validation failed" {
+		t.Errorf("Error() should return the message, got %s", downstream.Error())
+	}
+
+	// Parsing the wrapped error is lossless, including members gocerr cannot carry
+	parsed := NewResponseErrorVM().ParseError(err)
+	testResponseErrorVMEquality(t, downstream, parsed)
+
+	if parsed.ErrorCode != "INVALID_ORDER" || parsed.Conflict == nil {
+		t.Error("ParseError should keep the error code and conflict")
+	}
+
+	if parsed.ErrorFields[0] == downstream.ErrorFields[0] {
+		t.Error("ParseError should copy the error fields")
+	}
+}
+
+// TestResponseErrorVM_ToGocerr tests the round trip through gocerr.Error
+func TestResponseErrorVM_ToGocerr(t *testing.T) {
+	var testCases = []struct {
+		Name string
+		VM   *ResponseErrorVM
+		Code int
+	}{
+		{
+			Name: "message only",
+			VM:   NewResponseErrorVM().SetMessage("not found"),
+			Code: http.StatusNotFound,
+		},
+		{
+			Name: "with error fields",
+			VM: NewResponseErrorVM().
+				SetMessage("validation failed").
+				AddErrorFields(
+					NewResponseErrorFieldVM("name", "name is required"),
+					NewResponseErrorFieldVM("email", "email is invalid"),
+				),
+			Code: http.StatusBadRequest,
+		},
+	}
+
+	for i := range testCases {
+		t.Run(testCases[i].Name, func(t *testing.T) {
+			customErr := testCases[i].VM.ToGocerr(testCases[i].Code)
+
+			if customErr.Code != testCases[i].Code {
+				t.Errorf("expected code is %d, got %d", testCases[i].Code, customErr.Code)
+			}
+
+			testResponseErrorVMEquality(t, testCases[i].VM, NewResponseErrorVM().ParseError(customErr))
+
+			response := NewResponseVM[*someStruct]().SetErrorFromError(customErr)
+			if response.Code != testCases[i].Code {
+				t.Errorf("expected response code is %d, got %d", testCases[i].Code, response.Code)
+			}
+		})
+	}
+}
+
+// TestResponseErrorVM_ParseError_RichFields tests that rich field errors survive parsing and merging
+func TestResponseErrorVM_ParseError_RichFields(t *testing.T) {
+	queryErr := NewResponseErrorVM().
+		SetMessage("invalid query").
+		AddErrorFields(NewResponseErrorFieldVM("limit", "too large").SetLocation(LocationQuery).SetCode("max").SetRejectedValue(500))
+
+	joined := errors.Join(
+		queryErr,
+		gocerr.New(http.StatusBadRequest, "invalid body", gocerr.NewErrorField("name", "name is required")),
+	)
+
+	expected := NewResponseErrorVM().
+		SetMessage("invalid query; invalid body").
+		AddErrorFields(
+			NewResponseErrorFieldVM("limit", "too large").SetLocation(LocationQuery).SetCode("max").SetRejectedValue(500),
+			NewResponseErrorFieldVM("name", "name is required"),
+		)
+
+	response := NewResponseVM[*someStruct]().SetErrorFromError(joined)
+	testResponseErrorVMEquality(t, expected, response.Error)
+
+	if response.Code != http.StatusBadRequest {
+		t.Errorf("expected code is %d, got %d", http.StatusBadRequest, response.Code)
+	}
+}
+
+// TestResponseErrorVM_ParseError_GocerrFieldDetails tests that field errors joined with a gocerr.Error fill in its fields
+func TestResponseErrorVM_ParseError_GocerrFieldDetails(t *testing.T) {
+	invalid := func() gocerr.Error {
+		return gocerr.New(http.StatusUnprocessableEntity, "invalid input", gocerr.NewErrorField("page", "must be positive"))
+	}
+
+	testCases := []struct {
+		Name     string
+		Err      error
+		Expected []*ResponseErrorFieldVM
+	}{
+		{
+			Name:     "GocerrOnly",
+			Err:      invalid(),
+			Expected: []*ResponseErrorFieldVM{NewResponseErrorFieldVM("page", "must be positive")},
+		},
+		{
+			Name: "Enriched",
+			Err: errors.Join(invalid(),
+				NewResponseErrorFieldVM("page", "must be positive").SetLocation(LocationQuery).SetCode("min").SetRejectedValue(-1)),
+			Expected: []*ResponseErrorFieldVM{
+				NewResponseErrorFieldVM("page", "must be positive").SetLocation(LocationQuery).SetCode("min").SetRejectedValue(-1),
+			},
+		},
+		{
+			Name: "Wrapped",
+			Err: errors.Join(invalid(),
+				fmt.Errorf("parse page: %w", NewResponseErrorFieldVM("page", "must be positive").SetLocation(LocationQuery))),
+			Expected: []*ResponseErrorFieldVM{
+				NewResponseErrorFieldVM("page", "must be positive").SetLocation(LocationQuery),
+			},
+		},
+		{
+			Name: "AdditionalField",
+			Err: errors.Join(invalid(),
+				NewResponseErrorFieldVM("X-Tenant", "is required").SetLocation(LocationHeader).SetCode("required")),
+			Expected: []*ResponseErrorFieldVM{
+				NewResponseErrorFieldVM("page", "must be positive"),
+				NewResponseErrorFieldVM("X-Tenant", "is required").SetLocation(LocationHeader).SetCode("required"),
+			},
+		},
+		{
+			Name: "ConflictingLocation",
+			Err: errors.Join(
+				invalid(),
+				NewResponseErrorFieldVM("page", "must be positive").SetLocation(LocationQuery),
+				NewResponseErrorFieldVM("page", "must be positive").SetLocation(LocationBody),
+			),
+			Expected: []*ResponseErrorFieldVM{
+				NewResponseErrorFieldVM("page", "must be positive").SetLocation(LocationQuery),
+				NewResponseErrorFieldVM("page", "must be positive").SetLocation(LocationBody),
+			},
+		},
+	}
+
+	for i := range testCases {
+		t.Run(testCases[i].Name, func(t *testing.T) {
+			response := NewResponseVM[*someStruct]().SetErrorFromError(testCases[i].Err)
+
+			expected := NewResponseErrorVM().SetMessage("invalid input").AddErrorFields(testCases[i].Expected...)
+			testResponseErrorVMEquality(t, expected, response.Error)
+
+			if response.Code != http.StatusUnprocessableEntity {
+				t.Errorf("expected code is %d, got %d", http.StatusUnprocessableEntity, response.Code)
+			}
+		})
+	}
+}
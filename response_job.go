@@ -0,0 +1,144 @@
+package gores
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// JobState represents the lifecycle state of an asynchronous job.
+type JobState string
+
+const (
+	JobStatePending   JobState = "pending"   // Job accepted but not started
+	JobStateRunning   JobState = "running"   // Job in progress
+	JobStateSucceeded JobState = "succeeded" // Job completed successfully
+	JobStateFailed    JobState = "failed"    // Job terminated with an error
+	JobStateCanceled  JobState = "canceled"  // Job canceled before completion
+)
+
+// IsTerminal reports whether the state is final and clients should stop polling.
+func (s JobState) IsTerminal() bool {
+	return s == JobStateSucceeded || s == JobStateFailed || s == JobStateCanceled
+}
+
+// JobVM describes an asynchronous job in 202 Accepted and status polling responses.
+// Terminal failures embed a ResponseErrorVM so job errors share the regular error shape.
+type JobVM struct {
+	ID         string           `json:"id"`                    // Unique job identifier
+	State      JobState         `json:"state"`                 // Current lifecycle state
+	Progress   int              `json:"progress"`              // Completion percentage from 0 to 100
+	StatusURL  string           `json:"status_url,omitempty"`  // URL to poll for job status
+	RetryAfter int              `json:"retry_after,omitempty"` // Suggested polling interval in seconds
+	Result     interface{}      `json:"result,omitempty"`      // Job output once succeeded
+	Error      *ResponseErrorVM `json:"error,omitempty"`       // Error details once failed
+	CreatedAt  time.Time        `json:"created_at"`            // Time the job was accepted
+	UpdatedAt  time.Time        `json:"updated_at"`            // Time of the last state change
+}
+
+// NewJobVM creates a new pending job with the given identifier.
+// An empty identifier is filled in by JobStore.Create.
+func NewJobVM(id string) *JobVM {
+	now := time.Now().UTC()
+
+	return &JobVM{
+		ID:        id,
+		State:     JobStatePending,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+}
+
+// SetState sets the lifecycle state of the job and refreshes UpdatedAt.
+// Moving to JobStateSucceeded also sets the progress to 100.
+func (vm *JobVM) SetState(state JobState) *JobVM {
+	vm.State = state
+	vm.UpdatedAt = time.Now().UTC()
+
+	if state == JobStateSucceeded {
+		vm.Progress = 100
+	}
+
+	return vm
+}
+
+// SetProgress sets the completion percentage, clamped to the 0-100 range.
+// This method follows the fluent API pattern for method chaining.
+func (vm *JobVM) SetProgress(progress int) *JobVM {
+	if progress < 0 {
+		progress = 0
+	}
+
+	if progress > 100 {
+		progress = 100
+	}
+
+	vm.Progress = progress
+	vm.UpdatedAt = time.Now().UTC()
+	return vm
+}
+
+// SetStatusURL sets the URL clients poll for job status.
+// This method follows the fluent API pattern for method chaining.
+func (vm *JobVM) SetStatusURL(statusURL string) *JobVM {
+	vm.StatusURL = statusURL
+	return vm
+}
+
+// SetRetryAfter sets the suggested polling interval, rounded up to whole seconds.
+// This method follows the fluent API pattern for method chaining.
+func (vm *JobVM) SetRetryAfter(retryAfter time.Duration) *JobVM {
+	vm.RetryAfter = ceilSeconds(retryAfter)
+	return vm
+}
+
+// SetResult marks the job as succeeded with the given output.
+// This method follows the fluent API pattern for method chaining.
+func (vm *JobVM) SetResult(result interface{}) *JobVM {
+	vm.Result = result
+	return vm.SetState(JobStateSucceeded)
+}
+
+// SetErrorFromError marks the job as failed with error details parsed from err.
+// For nil errors the job is left unchanged.
+func (vm *JobVM) SetErrorFromError(err error) *JobVM {
+	if err == nil {
+		return vm
+	}
+
+	vm.Error = NewResponseErrorVM().ParseError(err)
+	return vm.SetState(JobStateFailed)
+}
+
+// NewAcceptedResponseVM creates a 202 Accepted response describing a newly started job.
+// The Renderer writes the status URL as Location and the polling interval as Retry-After.
+func NewAcceptedResponseVM(job *JobVM) *ResponseVM[*JobVM] {
+	response := NewJobStatusResponseVM(job).
+		SetCode(http.StatusAccepted)
+
+	if job != nil && job.StatusURL != "" {
+		response.SetHeader("Location", job.StatusURL)
+	}
+
+	return response
+}
+
+// NewJobStatusResponseVM creates a 200 OK response reporting the current job status.
+// Non-terminal jobs carry Retry-After so clients know when to poll again; a failed job
+// is still a successful poll, with the failure described in the job's Error field.
+// Location is not sent, since a 200 response is already the status resource itself.
+func NewJobStatusResponseVM(job *JobVM) *ResponseVM[*JobVM] {
+	response := NewResponseVM[*JobVM]().
+		SetCode(http.StatusOK).
+		SetData(job)
+
+	if job == nil {
+		return response
+	}
+
+	if job.RetryAfter > 0 && !job.State.IsTerminal() {
+		response.SetHeader("Retry-After", strconv.Itoa(job.RetryAfter))
+	}
+
+	return response
+}
@@ -0,0 +1,108 @@
+package gores
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestJobVM(t *testing.T) {
+	testCases := []struct {
+		Name             string
+		Job              *JobVM
+		ExpectedState    JobState
+		ExpectedProgress int
+		ExpectError      bool
+	}{
+		{Name: "NewJobVM", Job: NewJobVM("job-1"), ExpectedState: JobStatePending},
+		{Name: "SetProgress", Job: NewJobVM("job-1").SetState(JobStateRunning).SetProgress(40), ExpectedState: JobStateRunning, ExpectedProgress: 40},
+		{Name: "SetProgress_Clamped", Job: NewJobVM("job-1").SetProgress(140), ExpectedState: JobStatePending, ExpectedProgress: 100},
+		{Name: "SetResult", Job: NewJobVM("job-1").SetResult("done"), ExpectedState: JobStateSucceeded, ExpectedProgress: 100},
+		{Name: "SetErrorFromError", Job: NewJobVM("job-1").SetErrorFromError(errors.New("import failed")), ExpectedState: JobStateFailed, ExpectError: true},
+		{Name: "SetErrorFromError_Nil", Job: NewJobVM("job-1").SetErrorFromError(nil), ExpectedState: JobStatePending},
+	}
+
+	for i := range testCases {
+		t.Run(testCases[i].Name, func(t *testing.T) {
+			job := testCases[i].Job
+
+			if job.State != testCases[i].ExpectedState {
+				t.Errorf("expected state is %s, got %s", testCases[i].ExpectedState, job.State)
+			}
+
+			if job.Progress != testCases[i].ExpectedProgress {
+				t.Errorf("expected progress is %d, got %d", testCases[i].ExpectedProgress, job.Progress)
+			}
+
+			if (job.Error != nil) != testCases[i].ExpectError {
+				t.Errorf("expected error presence is %t, got %t", testCases[i].ExpectError, job.Error != nil)
+			}
+		})
+	}
+}
+
+func TestJobState_IsTerminal(t *testing.T) {
+	testCases := map[JobState]bool{
+		JobStatePending:   false,
+		JobStateRunning:   false,
+		JobStateSucceeded: true,
+		JobStateFailed:    true,
+		JobStateCanceled:  true,
+	}
+
+	for state, expected := range testCases {
+		if actual := state.IsTerminal(); actual != expected {
+			t.Errorf("expected %s terminal is %t, got %t", state, expected, actual)
+		}
+	}
+}
+
+func TestNewAcceptedResponseVM(t *testing.T) {
+	job := NewJobVM("job-1").
+		SetStatusURL("/jobs/job-1").
+		SetRetryAfter(1500 * time.Millisecond)
+
+	w := httptest.NewRecorder()
+	if err := NewRenderer().Render(w, httptest.NewRequest(http.MethodPost, "/imports", nil), NewAcceptedResponseVM(job)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if w.Code != http.StatusAccepted {
+		t.Errorf("expected code is %d, got %d", http.StatusAccepted, w.Code)
+	}
+
+	if w.Header().Get("Location") != "/jobs/job-1" {
+		t.Errorf("expected location is %s, got %s", "/jobs/job-1", w.Header().Get("Location"))
+	}
+
+	if w.Header().Get("Retry-After") != "2" {
+		t.Errorf("expected retry after is %s, got %s", "2", w.Header().Get("Retry-After"))
+	}
+}
+
+func TestNewJobStatusResponseVM_Terminal(t *testing.T) {
+	job := NewJobVM("job-1").
+		SetStatusURL("/jobs/job-1").
+		SetRetryAfter(time.Second).
+		SetErrorFromError(errors.New("import failed"))
+
+	response := NewJobStatusResponseVM(job)
+
+	if response.Code != http.StatusOK {
+		t.Errorf("expected code is %d, got %d", http.StatusOK, response.Code)
+	}
+
+	if response.headers().Get("Retry-After") != "" {
+		t.Error("expected no retry after header for terminal job")
+	}
+
+	if response.headers().Get("Location") != "" {
+		t.Error("expected no location header for a status poll")
+	}
+
+	if response.Data.Error == nil || response.Data.Error.Message != "import failed" {
+		t.Error("expected job error to be embedded")
+	}
+}
@@ -0,0 +1,232 @@
+package gores
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"sync"
+)
+
+// partialError is a recorded failure of one part of a partial response.
+type partialError struct {
+	code int              // HTTP status code derived from the error
+	vm   *ResponseErrorVM // Error details tagged with the affected path
+}
+
+// PartialErrors collects the outcomes of the independent parts of an aggregated
+// response, such as calls to several backends. It is safe for concurrent use,
+// so parts may report their outcome from their own goroutines.
+type PartialErrors struct {
+	mu     sync.Mutex
+	wg     sync.WaitGroup
+	total  int
+	errors []partialError
+}
+
+// NewPartialErrors creates a new empty collector.
+func NewPartialErrors() *PartialErrors {
+	return &PartialErrors{}
+}
+
+// Add records the outcome of the part at path. A nil error counts as a success.
+// Errors are mapped exactly as in ResponseVM.SetErrorFromError and tagged with path.
+func (p *PartialErrors) Add(path string, err error) *PartialErrors {
+	var failure *partialError
+	if err != nil {
+		response := NewResponseVM[*struct{}]().SetErrorFromError(err)
+		failure = &partialError{code: response.Code, vm: response.Error.SetPath(path)}
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.total++
+	if failure != nil {
+		p.errors = append(p.errors, *failure)
+	}
+
+	return p
+}
+
+// Go runs fn in a new goroutine and records its outcome under path.
+// Call Wait before reading the collected errors.
+func (p *PartialErrors) Go(path string, fn func() error) {
+	p.wg.Add(1)
+	go func() {
+		defer p.wg.Done()
+		p.Add(path, fn())
+	}()
+}
+
+// Wait blocks until every function started with Go has returned.
+// This method uses method chaining pattern for fluent API design.
+func (p *PartialErrors) Wait() *PartialErrors {
+	p.wg.Wait()
+	return p
+}
+
+// Total returns the number of recorded parts, successful or not.
+func (p *PartialErrors) Total() int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	return p.total
+}
+
+// Failed returns the number of recorded parts that failed.
+func (p *PartialErrors) Failed() int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	return len(p.errors)
+}
+
+// Errors returns the collected errors ordered by path, so that responses are
+// deterministic regardless of the order in which goroutines finished.
+func (p *PartialErrors) Errors() []*ResponseErrorVM {
+	failures := p.snapshot()
+
+	errors := make([]*ResponseErrorVM, 0, len(failures))
+	for _, failure := range failures {
+		errors = append(errors, failure.vm)
+	}
+
+	return errors
+}
+
+// snapshot returns a copy of the recorded failures ordered by path.
+func (p *PartialErrors) snapshot() []partialError {
+	p.mu.Lock()
+	failures := append([]partialError(nil), p.errors...)
+	p.mu.Unlock()
+
+	sort.SliceStable(failures, func(i, j int) bool {
+		return failures[i].vm.Path < failures[j].vm.Path
+	})
+
+	return failures
+}
+
+// PartialPolicy decides when partial failure escalates to a full error status.
+// By default a response only escalates when every recorded part failed.
+type PartialPolicy struct {
+	maxErrors     int             // Errors tolerated before escalating, 0 for no limit
+	requiredPaths map[string]bool // Paths whose failure always escalates
+	code          int             // Status code on escalation, 0 to derive it from the errors
+}
+
+// NewPartialPolicy creates a new policy that escalates only when every part failed.
+func NewPartialPolicy() *PartialPolicy {
+	return &PartialPolicy{
+		requiredPaths: make(map[string]bool),
+	}
+}
+
+// SetMaxErrors sets how many failed parts are tolerated before escalating.
+// Zero, the default, tolerates any number as long as one part succeeded.
+func (pp *PartialPolicy) SetMaxErrors(maxErrors int) *PartialPolicy {
+	pp.maxErrors = maxErrors
+	return pp
+}
+
+// AddRequiredPaths marks parts without which the response is useless.
+// The failure of any of them escalates regardless of the other settings.
+func (pp *PartialPolicy) AddRequiredPaths(paths ...string) *PartialPolicy {
+	for _, path := range paths {
+		pp.requiredPaths[path] = true
+	}
+
+	return pp
+}
+
+// SetCode sets the status code used on escalation. By default the common code of
+// the failed parts is used, or 500 Internal Server Error when they differ.
+func (pp *PartialPolicy) SetCode(code int) *PartialPolicy {
+	pp.code = code
+	return pp
+}
+
+// escalates reports whether the recorded failures turn the response into an error.
+func (pp *PartialPolicy) escalates(total int, failures []partialError) bool {
+	if len(failures) == 0 {
+		return false
+	}
+
+	if len(failures) >= total {
+		return true
+	}
+
+	if pp.maxErrors > 0 && len(failures) > pp.maxErrors {
+		return true
+	}
+
+	for _, failure := range failures {
+		if pp.requiredPaths[failure.vm.Path] {
+			return true
+		}
+	}
+
+	return false
+}
+
+// statusCode returns the status code of an escalated response.
+func (pp *PartialPolicy) statusCode(failures []partialError) int {
+	if pp.code != 0 {
+		return pp.code
+	}
+
+	code := failures[0].code
+	for _, failure := range failures[1:] {
+		if failure.code != code {
+			return http.StatusInternalServerError
+		}
+	}
+
+	return code
+}
+
+// AddErrors appends errors describing parts of the response that could not be produced.
+// Nil errors are skipped. Unlike Error they do not turn the response into a failure.
+func (vm *ResponseVM[T]) AddErrors(errs ...*ResponseErrorVM) *ResponseVM[T] {
+	for _, err := range errs {
+		if err == nil {
+			continue
+		}
+
+		if vm.Errors == nil {
+			vm.Errors = &[]*ResponseErrorVM{}
+		}
+		*vm.Errors = append(*vm.Errors, err)
+	}
+
+	return vm
+}
+
+// SetPartialErrors attaches the errors collected for a partial response, keeping Data.
+// When the policy escalates, the response also gets an error status code and a summary
+// Error. A nil policy uses NewPartialPolicy. The collector should be waited on first.
+func (vm *ResponseVM[T]) SetPartialErrors(errs *PartialErrors, policy *PartialPolicy) *ResponseVM[T] {
+	if errs == nil {
+		return vm
+	}
+
+	if policy == nil {
+		policy = NewPartialPolicy()
+	}
+
+	total := errs.Total()
+	failures := errs.snapshot()
+	for _, failure := range failures {
+		vm.AddErrors(failure.vm)
+	}
+
+	if !policy.escalates(total, failures) {
+		return vm
+	}
+
+	vm.Code = policy.statusCode(failures)
+	vm.Error = NewResponseErrorVM().
+		SetMessage(fmt.Sprintf("%d of %d parts failed", len(failures), total))
+
+	return vm
+}
@@ -0,0 +1,167 @@
+package gores
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/fikri240794/gocerr"
+)
+
+func TestPartialErrors_Go(t *testing.T) {
+	collector := NewPartialErrors()
+
+	collector.Go("users", func() error { return nil })
+	collector.Go("orders", func() error { return gocerr.New(http.StatusServiceUnavailable, "orders service unavailable") })
+	collector.Go("invoices", func() error { return errors.New("connection reset") })
+	collector.Go("profile", func() error { return nil })
+	collector.Wait()
+
+	if collector.Total() != 4 || collector.Failed() != 2 {
+		t.Fatalf("expected 2 of 4 parts failed, got %d of %d", collector.Failed(), collector.Total())
+	}
+
+	errs := collector.Errors()
+	if errs[0].Path != "invoices" || errs[1].Path != "orders" {
+		t.Errorf("expected errors ordered by path, got %s, %s", errs[0].Path, errs[1].Path)
+	}
+
+	if !errs[1].Retryable {
+		t.Error("expected 503 part error to be retryable")
+	}
+}
+
+func TestResponseVM_SetPartialErrors(t *testing.T) {
+	var testCases = []struct {
+		Name          string
+		Outcomes      map[string]error
+		Policy        *PartialPolicy
+		ExpectedCode  int
+		ExpectedError bool
+	}{
+		{
+			Name:         "all parts succeeded",
+			Outcomes:     map[string]error{"a": nil, "b": nil},
+			Policy:       nil,
+			ExpectedCode: http.StatusOK,
+		},
+		{
+			Name:         "some parts failed",
+			Outcomes:     map[string]error{"a": nil, "b": gocerr.New(http.StatusBadGateway, "b failed")},
+			Policy:       nil,
+			ExpectedCode: http.StatusOK,
+		},
+		{
+			Name:          "all parts failed with the same code",
+			Outcomes:      map[string]error{"a": gocerr.New(http.StatusBadGateway, "a failed"), "b": gocerr.New(http.StatusBadGateway, "b failed")},
+			Policy:        nil,
+			ExpectedCode:  http.StatusBadGateway,
+			ExpectedError: true,
+		},
+		{
+			Name:          "all parts failed with different codes",
+			Outcomes:      map[string]error{"a": gocerr.New(http.StatusBadGateway, "a failed"), "b": gocerr.New(http.StatusNotFound, "b failed")},
+			Policy:        nil,
+			ExpectedCode:  http.StatusInternalServerError,
+			ExpectedError: true,
+		},
+		{
+			Name:          "required part failed",
+			Outcomes:      map[string]error{"a": nil, "b": errors.New("b failed")},
+			Policy:        NewPartialPolicy().AddRequiredPaths("b").SetCode(http.StatusBadGateway),
+			ExpectedCode:  http.StatusBadGateway,
+			ExpectedError: true,
+		},
+		{
+			Name:          "too many parts failed",
+			Outcomes:      map[string]error{"a": nil, "b": errors.New("b failed"), "c": errors.New("c failed")},
+			Policy:        NewPartialPolicy().SetMaxErrors(1),
+			ExpectedCode:  http.StatusInternalServerError,
+			ExpectedError: true,
+		},
+		{
+			Name:         "failures within the limit",
+			Outcomes:     map[string]error{"a": nil, "b": errors.New("b failed"), "c": nil},
+			Policy:       NewPartialPolicy().SetMaxErrors(1),
+			ExpectedCode: http.StatusOK,
+		},
+	}
+
+	for i := range testCases {
+		t.Run(testCases[i].Name, func(t *testing.T) {
+			collector := NewPartialErrors()
+			failed := 0
+			for path, err := range testCases[i].Outcomes {
+				collector.Add(path, err)
+				if err != nil {
+					failed++
+				}
+			}
+
+			vm := NewResponseVM[*someStruct]().
+				SetCode(http.StatusOK).
+				SetData(&someStruct{SomeField: "value"}).
+				SetPartialErrors(collector, testCases[i].Policy)
+
+			if vm.Code != testCases[i].ExpectedCode {
+				t.Errorf("expected code is %d, got %d", testCases[i].ExpectedCode, vm.Code)
+			}
+
+			if (vm.Error != nil) != testCases[i].ExpectedError {
+				t.Errorf("expected error presence is %v, got %v", testCases[i].ExpectedError, vm.Error != nil)
+			}
+
+			var errs []*ResponseErrorVM
+			if vm.Errors != nil {
+				errs = *vm.Errors
+			}
+
+			if len(errs) != failed {
+				t.Errorf("expected length of errors is %d, got %d", failed, len(errs))
+			}
+
+			if vm.Data == nil {
+				t.Error("expected data to be kept")
+			}
+		})
+	}
+}
+
+func TestResponseVM_PartialJSON(t *testing.T) {
+	vm := NewResponseVM[*someStruct]().
+		SetCode(http.StatusOK).
+		SetData(&someStruct{SomeField: "value"}).
+		SetPartialErrors(NewPartialErrors().
+			Add("users", nil).
+			Add("orders", gocerr.New(http.StatusNotFound, "orders not found")), nil)
+
+	body, err := json.Marshal(vm)
+	if err != nil {
+		t.Fatalf("expected error is nil, got %v", err)
+	}
+
+	expected := `{"code":200,"errors":[{"message":"orders not found","path":"orders"}],"data":{"SomeField":"value"}}`
+	if string(body) != expected {
+		t.Errorf("expected body is %s, got %s", expected, string(body))
+	}
+
+	encoded, err := NewEnvelopeSchema().SetKeyCase(CamelCase).SetKey("path", "location").Encode(body)
+	if err != nil {
+		t.Fatalf("expected error is nil, got %v", err)
+	}
+
+	expected = `{"code":200,"errors":[{"message":"orders not found","location":"orders"}],"data":{"SomeField":"value"}}`
+	if string(encoded) != expected {
+		t.Errorf("expected encoded body is %s, got %s", expected, string(encoded))
+	}
+
+	decoded, err := NewEnvelopeSchema().SetKeyCase(CamelCase).SetKey("path", "location").Decode(encoded)
+	if err != nil {
+		t.Fatalf("expected error is nil, got %v", err)
+	}
+
+	if string(decoded) != string(body) {
+		t.Errorf("expected decoded body is %s, got %s", string(body), string(decoded))
+	}
+}
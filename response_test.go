@@ -279,3 +279,18 @@ func TestResponseVM_MethodChaining(t *testing.T) {
 		t.Error("SetErrorFromError should return the same instance for method chaining")
 	}
 }
+
+// TestResponseVM_Comparable tests that responses remain comparable with ==
+func TestResponseVM_Comparable(t *testing.T) {
+	first := *NewResponseVM[int]().SetCode(http.StatusOK).SetData(42)
+	second := *NewResponseVM[int]().SetCode(http.StatusOK).SetData(42)
+
+	if first != second {
+		t.Error("expected equal responses to compare equal")
+	}
+
+	withHeader := *NewResponseVM[int]().SetCode(http.StatusOK).SetData(42).SetHeader("Location", "/items/42")
+	if withHeader == first {
+		t.Error("expected responses with headers to differ")
+	}
+}
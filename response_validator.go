@@ -0,0 +1,380 @@
+package gores
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"log"
+	"math"
+	"net/http"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ValidationMode controls what a ResponseValidator does with a response that
+// violates its schema.
+type ValidationMode int
+
+const (
+	ValidationOff   ValidationMode = iota // Do not validate (default, for production)
+	ValidationLog                         // Log violations and write the response anyway (development)
+	ValidationPanic                       // Panic with a *SchemaValidationError (tests calling handlers directly)
+)
+
+// SchemaViolation describes a single place where a JSON document breaks its schema.
+type SchemaViolation struct {
+	Path    string // JSON Pointer of the offending value, "" for the document itself
+	Rule    string // Schema keyword that failed, e.g. "type" or "required"
+	Message string // Human-readable description of the violation
+}
+
+// String formats the violation as "path: message (rule)".
+func (v SchemaViolation) String() string {
+	path := v.Path
+	if path == "" {
+		path = "/"
+	}
+
+	return path + ": " + v.Message + " (" + v.Rule + ")"
+}
+
+// SchemaValidationError reports every violation found in a JSON document.
+type SchemaValidationError struct {
+	Violations []SchemaViolation // Violations in traversal order, object members sorted by key
+}
+
+// Error lists the violations, one per line.
+func (e *SchemaValidationError) Error() string {
+	lines := make([]string, 0, len(e.Violations)+1)
+	lines = append(lines, fmt.Sprintf("gores: response violates its schema (%d violations)", len(e.Violations)))
+	for _, violation := range e.Violations {
+		lines = append(lines, "  "+violation.String())
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+// ValidateJSON validates a JSON document against schema, resolving $ref against components.
+// It returns a *SchemaValidationError listing every violation, or nil when the document conforms.
+func ValidateJSON(body []byte, schema *Schema, components map[string]*Schema) error {
+	return validateJSON(body, schema, components, -1)
+}
+
+// validateJSON validates a JSON document, not enforcing required members at partialFrom or
+// deeper, since sparse fieldsets drop them; a negative partialFrom enforces them everywhere.
+func validateJSON(body []byte, schema *Schema, components map[string]*Schema, partialFrom int) error {
+	decoder := json.NewDecoder(bytes.NewReader(body))
+	decoder.UseNumber()
+
+	var document interface{}
+	if err := decoder.Decode(&document); err != nil {
+		return err
+	}
+
+	validator := schemaValidator{components: components, partialFrom: partialFrom}
+	validator.validate(document, schema, FieldPath{})
+
+	if len(validator.violations) == 0 {
+		return nil
+	}
+
+	return &SchemaValidationError{Violations: validator.violations}
+}
+
+// schemaValidator walks a decoded JSON document alongside its schema.
+type schemaValidator struct {
+	components  map[string]*Schema
+	violations  []SchemaViolation
+	partialFrom int // Path depth from which required members are not enforced, -1 for none
+}
+
+// report records a violation at path.
+func (sv *schemaValidator) report(path FieldPath, rule, format string, args ...interface{}) {
+	sv.violations = append(sv.violations, SchemaViolation{
+		Path:    path.JSONPointer(),
+		Rule:    rule,
+		Message: fmt.Sprintf(format, args...),
+	})
+}
+
+// validate checks value against schema and records violations.
+func (sv *schemaValidator) validate(value interface{}, schema *Schema, path FieldPath) {
+	if schema == nil {
+		return
+	}
+
+	if schema.Ref != "" {
+		resolved := sv.components[strings.TrimPrefix(schema.Ref, openAPIComponentPrefix)]
+		if resolved == nil {
+			sv.report(path, "$ref", "unresolved reference %s", schema.Ref)
+			return
+		}
+
+		sv.validate(value, resolved, path)
+	}
+
+	if len(schema.AnyOf) > 0 && !sv.matchesAny(value, schema.AnyOf, path) {
+		sv.report(path, "anyOf", "value matches none of the %d allowed schemas", len(schema.AnyOf))
+	}
+
+	if schema.Type != "" && !matchesType(value, schema.Type) {
+		sv.report(path, "type", "expected %s, got %s", schema.Type, jsonTypeOf(value))
+		return
+	}
+
+	if len(schema.Enum) > 0 && !matchesEnum(value, schema.Enum) {
+		sv.report(path, "enum", "value %v is not one of %v", value, schema.Enum)
+	}
+
+	if schema.Format == "date-time" {
+		if text, ok := value.(string); ok {
+			if _, err := time.Parse(time.RFC3339, text); err != nil {
+				sv.report(path, "format", "%q is not an RFC 3339 date-time", text)
+			}
+		}
+	}
+
+	switch typed := value.(type) {
+	case map[string]interface{}:
+		sv.validateObject(typed, schema, path)
+	case []interface{}:
+		if schema.Items != nil {
+			for i, item := range typed {
+				sv.validate(item, schema.Items, path.Index(i))
+			}
+		}
+	}
+}
+
+// validateObject checks required members, declared properties and additional properties.
+func (sv *schemaValidator) validateObject(object map[string]interface{}, schema *Schema, path FieldPath) {
+	if sv.partialFrom < 0 || len(path) < sv.partialFrom {
+		for _, name := range schema.Required {
+			if _, exists := object[name]; !exists {
+				sv.report(path, "required", "missing required member %q", name)
+			}
+		}
+	}
+
+	keys := make([]string, 0, len(object))
+	for key := range object {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		if property, declared := schema.Properties[key]; declared {
+			sv.validate(object[key], property, path.Key(key))
+		} else if schema.AdditionalProperties != nil {
+			sv.validate(object[key], schema.AdditionalProperties, path.Key(key))
+		}
+	}
+}
+
+// matchesAny reports whether value conforms to at least one of schemas.
+func (sv *schemaValidator) matchesAny(value interface{}, schemas []*Schema, path FieldPath) bool {
+	for _, schema := range schemas {
+		probe := schemaValidator{components: sv.components, partialFrom: sv.partialFrom}
+		probe.validate(value, schema, path)
+
+		if len(probe.violations) == 0 {
+			return true
+		}
+	}
+
+	return false
+}
+
+// matchesType reports whether value is of the JSON Schema type name.
+func matchesType(value interface{}, name string) bool {
+	actual := jsonTypeOf(value)
+	if name == "number" && actual == "integer" {
+		return true
+	}
+
+	return actual == name
+}
+
+// jsonTypeOf returns the JSON Schema type name of a decoded value.
+func jsonTypeOf(value interface{}) string {
+	switch typed := value.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "boolean"
+	case string:
+		return "string"
+	case json.Number:
+		if number, err := typed.Float64(); err == nil && number == math.Trunc(number) {
+			return "integer"
+		}
+		return "number"
+	case []interface{}:
+		return "array"
+	case map[string]interface{}:
+		return "object"
+	}
+
+	return "unknown"
+}
+
+// matchesEnum reports whether value equals one of the allowed values once both are encoded as JSON.
+func matchesEnum(value interface{}, enum []interface{}) bool {
+	encoded, _ := json.Marshal(value)
+	for _, allowed := range enum {
+		if candidate, _ := json.Marshal(allowed); bytes.Equal(encoded, candidate) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// ResponseValidator checks every body a Renderer writes against a JSON Schema, catching
+// responses that drift from the published contract. By default the schema is generated from
+// the Go type of the envelope; SetSchemaFunc supplies hand-written ones. Bodies are validated
+// as written, after any EnvelopeSchema, bare mode and sparse fieldset is applied, and generated
+// schemas follow the same shape. Members left out by a sparse fieldset are not required.
+type ResponseValidator struct {
+	mu         sync.Mutex
+	mode       ValidationMode
+	logger     *log.Logger
+	reportFunc func(r *http.Request, err error)
+	schemaFunc func(r *http.Request, code int) *Schema
+	components map[string]*Schema
+	generators map[envelopeShape]*OpenAPIGenerator
+	generated  map[generatedSchemaKey]*Schema
+}
+
+// generatedSchemaKey identifies a generated body schema. Bare failed bodies carry the error
+// instead of the data, so failed is part of the key.
+type generatedSchemaKey struct {
+	shape        envelopeShape
+	envelopeType reflect.Type
+	failed       bool
+}
+
+// NewResponseValidator creates a new validator in the given mode that logs to the standard logger.
+func NewResponseValidator(mode ValidationMode) *ResponseValidator {
+	return &ResponseValidator{
+		mode:       mode,
+		logger:     log.Default(),
+		generators: make(map[envelopeShape]*OpenAPIGenerator),
+		generated:  make(map[generatedSchemaKey]*Schema),
+	}
+}
+
+// SetLogger sets the logger used in ValidationLog mode.
+// This method uses method chaining pattern for fluent API design.
+func (v *ResponseValidator) SetLogger(logger *log.Logger) *ResponseValidator {
+	v.logger = logger
+	return v
+}
+
+// SetReportFunc reports violations to report instead of logging them or panicking, in any mode
+// but ValidationOff. Tests pass func(r *http.Request, err error) { t.Error(err) }, since a panic
+// in a handler served by net/http is recovered and only logged, so the test would still pass.
+func (v *ResponseValidator) SetReportFunc(report func(r *http.Request, err error)) *ResponseValidator {
+	v.reportFunc = report
+	return v
+}
+
+// SetSchemaFunc supplies the schema of each response, e.g. looked up from a published
+// OpenAPI document by route and status code. Returning nil falls back to the generated schema.
+func (v *ResponseValidator) SetSchemaFunc(schemaFunc func(r *http.Request, code int) *Schema) *ResponseValidator {
+	v.schemaFunc = schemaFunc
+	return v
+}
+
+// SetComponents sets the component schemas that $ref in supplied schemas resolve against.
+// This method uses method chaining pattern for fluent API design.
+func (v *ResponseValidator) SetComponents(components map[string]*Schema) *ResponseValidator {
+	v.components = components
+	return v
+}
+
+// check validates the body encoded for the envelope in the given shape and reacts according to
+// the mode. Sparse bodies only keep the selected fields of the data, so their members are optional.
+func (v *ResponseValidator) check(r *http.Request, vm Envelope, body []byte, shape envelopeShape, sparse bool) error {
+	if v == nil || v.mode == ValidationOff {
+		return nil
+	}
+
+	partialFrom := -1
+	if sparse && shape.bare {
+		partialFrom = 0
+	} else if sparse {
+		partialFrom = 1
+	}
+
+	schema, components := v.schemaOf(r, vm, shape)
+
+	err := validateJSON(body, schema, components, partialFrom)
+	if err == nil {
+		return nil
+	}
+
+	if v.reportFunc != nil {
+		v.reportFunc(r, err)
+		return nil
+	}
+
+	if v.mode == ValidationPanic {
+		panic(err)
+	}
+
+	prefix := ""
+	if r != nil {
+		prefix = r.Method + " " + r.URL.Path + ": "
+	}
+	v.logger.Print(prefix + err.Error())
+
+	return nil
+}
+
+// schemaOf returns the schema for the body of the envelope and the components it refers to.
+func (v *ResponseValidator) schemaOf(r *http.Request, vm Envelope, shape envelopeShape) (*Schema, map[string]*Schema) {
+	if v.schemaFunc != nil {
+		code := vm.statusCode()
+		if code == 0 {
+			code = http.StatusOK
+		}
+
+		if schema := v.schemaFunc(r, code); schema != nil {
+			return schema, v.components
+		}
+	}
+
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	generator, exists := v.generators[shape]
+	if !exists {
+		generator = NewOpenAPIGenerator()
+		generator.shape = shape
+		v.generators[shape] = generator
+	}
+
+	key := generatedSchemaKey{
+		shape:        shape,
+		envelopeType: indirectType(reflect.TypeOf(vm)),
+		failed:       bareFailure(vm),
+	}
+
+	schema, exists := v.generated[key]
+	if !exists {
+		schema = generator.shapeEnvelope(generator.structSchema(key.envelopeType), key.failed)
+		v.generated[key] = schema
+	}
+
+	// Copy the components so validation does not race with later generation
+	components := make(map[string]*Schema, len(generator.Components()))
+	for name, component := range generator.Components() {
+		components[name] = component
+	}
+
+	return schema, components
+}
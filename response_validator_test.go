@@ -0,0 +1,286 @@
+package gores
+
+import (
+	"bytes"
+	"errors"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/fikri240794/gocerr"
+)
+
+func TestValidateJSON(t *testing.T) {
+	components := map[string]*Schema{
+		"Item": {
+			Type:       "object",
+			Properties: map[string]*Schema{"price": {Type: "number"}, "sku": {Type: "string"}},
+			Required:   []string{"sku"},
+		},
+	}
+	schema := &Schema{
+		Type: "object",
+		Properties: map[string]*Schema{
+			"code":       {Type: "integer"},
+			"status":     {Type: "string", Enum: []interface{}{"open", "closed"}},
+			"items":      {Type: "array", Items: &Schema{Ref: "#/components/schemas/Item"}},
+			"created_at": {Type: "string", Format: "date-time"},
+			"parent":     {AnyOf: []*Schema{{Ref: "#/components/schemas/Item"}, {Type: "null"}}},
+		},
+		Required: []string{"code"},
+	}
+
+	testCases := []struct {
+		Name     string
+		Body     string
+		Expected []string
+	}{
+		{
+			Name: "Valid",
+			Body: `{"code":200,"status":"open","items":[{"sku":"a","price":1.5}],"created_at":"2024-06-01T10:00:00Z","parent":null}`,
+		},
+		{
+			Name:     "MissingRequired",
+			Body:     `{}`,
+			Expected: []string{`/: missing required member "code" (required)`},
+		},
+		{
+			Name: "NestedViolations",
+			Body: `{"code":1.5,"status":"pending","items":[{"sku":"a"},{"price":"free"}],"created_at":"yesterday","parent":{"sku":1}}`,
+			Expected: []string{
+				"/code: expected integer, got number (type)",
+				"/created_at: \"yesterday\" is not an RFC 3339 date-time (format)",
+				`/items/1: missing required member "sku" (required)`,
+				"/items/1/price: expected number, got string (type)",
+				"/parent: value matches none of the 2 allowed schemas (anyOf)",
+				"/status: value pending is not one of [open closed] (enum)",
+			},
+		},
+	}
+
+	for i := range testCases {
+		t.Run(testCases[i].Name, func(t *testing.T) {
+			err := ValidateJSON([]byte(testCases[i].Body), schema, components)
+
+			if len(testCases[i].Expected) == 0 {
+				if err != nil {
+					t.Fatalf("expected error is nil, got %v", err)
+				}
+				return
+			}
+
+			var validationErr *SchemaValidationError
+			if !errors.As(err, &validationErr) {
+				t.Fatalf("expected *SchemaValidationError, got %v", err)
+			}
+
+			actual := make([]string, 0, len(validationErr.Violations))
+			for _, violation := range validationErr.Violations {
+				actual = append(actual, violation.String())
+			}
+
+			if strings.Join(actual, "\n") != strings.Join(testCases[i].Expected, "\n") {
+				t.Errorf("expected violations are\n%s\ngot\n%s", strings.Join(testCases[i].Expected, "\n"), strings.Join(actual, "\n"))
+			}
+		})
+	}
+}
+
+func TestRenderer_Validator(t *testing.T) {
+	strictUserSchema := func(r *http.Request, code int) *Schema {
+		if code != http.StatusOK {
+			return nil
+		}
+
+		return &Schema{
+			Type:       "object",
+			Properties: map[string]*Schema{"data": {Type: "object", Required: []string{"id"}}},
+			Required:   []string{"code", "data"},
+		}
+	}
+
+	t.Run("GeneratedSchemaAcceptsEnvelopes", func(t *testing.T) {
+		renderer := NewRenderer().SetValidator(NewResponseValidator(ValidationPanic))
+		request := httptest.NewRequest(http.MethodGet, "/", nil)
+
+		renderer.Render(httptest.NewRecorder(), request, NewResponseVM[*someStruct]().
+			SetCode(http.StatusOK).
+			SetData(&someStruct{SomeField: "value"}).
+			AddWarnings(NewResponseWarningVM(WarningCodeDeprecated, "deprecated")))
+
+		renderer.RenderError(httptest.NewRecorder(), request, gocerr.New(
+			http.StatusBadRequest,
+			"invalid",
+			gocerr.NewErrorField("name", "is required"),
+		))
+	})
+
+	t.Run("PanicMode", func(t *testing.T) {
+		defer func() {
+			var validationErr *SchemaValidationError
+			if err, _ := recover().(error); !errors.As(err, &validationErr) {
+				t.Fatalf("expected panic with *SchemaValidationError, got %v", err)
+			}
+
+			if validationErr.Violations[0].Path != "/data" {
+				t.Errorf("expected violation path is /data, got %s", validationErr.Violations[0].Path)
+			}
+		}()
+
+		renderer := NewRenderer().SetValidator(NewResponseValidator(ValidationPanic).SetSchemaFunc(strictUserSchema))
+		renderer.Render(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/users/1", nil), NewResponseVM[*someStruct]().
+			SetCode(http.StatusOK).
+			SetData(&someStruct{SomeField: "value"}))
+	})
+
+	t.Run("ReportFunc", func(t *testing.T) {
+		var reported []error
+		validator := NewResponseValidator(ValidationPanic).
+			SetSchemaFunc(strictUserSchema).
+			SetReportFunc(func(r *http.Request, err error) {
+				reported = append(reported, err)
+			})
+
+		// Served through net/http, where a panic would only be logged
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			NewRenderer().SetValidator(validator).Render(w, r, NewResponseVM[*someStruct]().SetCode(http.StatusOK))
+		}))
+		defer server.Close()
+
+		resp, err := server.Client().Get(server.URL + "/users/1")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		resp.Body.Close()
+
+		var validationErr *SchemaValidationError
+		if len(reported) != 1 || !errors.As(reported[0], &validationErr) {
+			t.Fatalf("expected one reported *SchemaValidationError, got %v", reported)
+		}
+
+		if resp.StatusCode != http.StatusOK {
+			t.Errorf("expected the response to be written, got %d", resp.StatusCode)
+		}
+	})
+
+	t.Run("WireShape", func(t *testing.T) {
+		type identified struct {
+			ID   int    `json:"id"`
+			Name string `json:"name"`
+		}
+
+		bareUserSchema := func(r *http.Request, code int) *Schema {
+			return &Schema{Type: "object", Properties: map[string]*Schema{"id": {Type: "integer"}}, Required: []string{"id"}}
+		}
+		renamedUserSchema := func(r *http.Request, code int) *Schema {
+			return &Schema{Type: "object", Properties: map[string]*Schema{"result": bareUserSchema(r, code)}, Required: []string{"result"}}
+		}
+
+		testCases := []struct {
+			Name       string
+			Renderer   *Renderer
+			SchemaFunc func(r *http.Request, code int) *Schema
+			URL        string
+			VM         Envelope
+			Expected   []string
+		}{
+			{
+				Name:       "BareMode",
+				Renderer:   NewRenderer().SetBareMode(true),
+				SchemaFunc: bareUserSchema,
+				VM:         NewResponseVM[*identified]().SetCode(http.StatusOK).SetData(&identified{ID: 1}),
+			},
+			{
+				Name:       "BareModeViolation",
+				Renderer:   NewRenderer().SetBareMode(true),
+				SchemaFunc: bareUserSchema,
+				VM:         NewResponseVM[*someStruct]().SetCode(http.StatusOK).SetData(&someStruct{SomeField: "value"}),
+				Expected:   []string{`/: missing required member "id" (required)`},
+			},
+			{
+				Name:     "BareModeGenerated",
+				Renderer: NewRenderer().SetBareMode(true).SetBareErrorKey("error"),
+				VM:       NewResponseVM[*identified]().SetErrorFromError(gocerr.New(http.StatusNotFound, "not found")),
+			},
+			{
+				Name:       "RenamedKeys",
+				Renderer:   NewRenderer().SetSchema(NewEnvelopeSchema().SetKey("data", "result")),
+				SchemaFunc: renamedUserSchema,
+				VM:         NewResponseVM[*identified]().SetCode(http.StatusOK).SetData(&identified{ID: 1}),
+			},
+			{
+				Name:     "RenamedKeysGenerated",
+				Renderer: NewRenderer().SetSchema(NewEnvelopeSchema().SetKey("data", "result").SetKey("message", "detail").SetFlattenError(true)),
+				VM: NewResponseVM[*identified]().SetErrorFromError(gocerr.New(
+					http.StatusBadRequest,
+					"invalid",
+					gocerr.NewErrorField("name", "is required"),
+				)),
+			},
+			{
+				Name:     "SparseFieldsGenerated",
+				Renderer: NewRenderer().SetFieldsParam("fields"),
+				URL:      "/?fields=id",
+				VM:       NewResponseVM[*identified]().SetCode(http.StatusOK).SetData(&identified{ID: 1, Name: "alice"}),
+			},
+		}
+
+		for i := range testCases {
+			t.Run(testCases[i].Name, func(t *testing.T) {
+				var actual []string
+				validator := NewResponseValidator(ValidationPanic).
+					SetSchemaFunc(testCases[i].SchemaFunc).
+					SetReportFunc(func(r *http.Request, err error) {
+						var validationErr *SchemaValidationError
+						if !errors.As(err, &validationErr) {
+							t.Fatalf("expected *SchemaValidationError, got %v", err)
+						}
+
+						for _, violation := range validationErr.Violations {
+							actual = append(actual, violation.String())
+						}
+					})
+
+				url := testCases[i].URL
+				if url == "" {
+					url = "/"
+				}
+
+				err := testCases[i].Renderer.SetValidator(validator).Render(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, url, nil), testCases[i].VM)
+				if err != nil {
+					t.Fatalf("unexpected error %v", err)
+				}
+
+				if strings.Join(actual, "\n") != strings.Join(testCases[i].Expected, "\n") {
+					t.Errorf("expected violations are %v, got %v", testCases[i].Expected, actual)
+				}
+			})
+		}
+	})
+
+	t.Run("LogMode", func(t *testing.T) {
+		var logs bytes.Buffer
+		validator := NewResponseValidator(ValidationLog).
+			SetSchemaFunc(strictUserSchema).
+			SetLogger(log.New(&logs, "", 0))
+
+		w := httptest.NewRecorder()
+		err := NewRenderer().SetValidator(validator).Render(w, httptest.NewRequest(http.MethodGet, "/users/1", nil), NewResponseVM[*someStruct]().
+			SetCode(http.StatusOK))
+
+		if err != nil {
+			t.Fatalf("expected error is nil, got %v", err)
+		}
+
+		if w.Code != http.StatusOK || w.Body.Len() == 0 {
+			t.Error("expected the response to be written in log mode")
+		}
+
+		if !strings.Contains(logs.String(), `GET /users/1: gores: response violates its schema (1 violations)`) ||
+			!strings.Contains(logs.String(), `/: missing required member "data" (required)`) {
+			t.Errorf("unexpected log output %q", logs.String())
+		}
+	})
+}
@@ -0,0 +1,150 @@
+package gores
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// WarningCodeDeprecated marks warnings about deprecated endpoints, parameters or fields.
+// The Renderer mirrors the deprecation date of such warnings in the Deprecation header.
+const WarningCodeDeprecated = "DEPRECATED"
+
+// ResponseWarningVM represents a non-fatal notice returned alongside successful data,
+// such as a deprecation or a partial-data notice.
+type ResponseWarningVM struct {
+	Code    string `json:"code"`            // Machine-readable warning code
+	Message string `json:"message"`         // Human-readable warning message
+	Field   string `json:"field,omitempty"` // Field the warning refers to, if any
+
+	deprecation time.Time // Date the deprecated resource was or will be deprecated
+}
+
+// NewResponseWarningVM creates a new warning with the specified code and message.
+// Use SetField to relate the warning to a specific field.
+func NewResponseWarningVM(code, message string) *ResponseWarningVM {
+	return &ResponseWarningVM{
+		Code:    code,
+		Message: message,
+	}
+}
+
+// SetField sets the field the warning refers to.
+// This method follows the fluent API pattern for method chaining.
+func (vm *ResponseWarningVM) SetField(field string) *ResponseWarningVM {
+	vm.Field = field
+	return vm
+}
+
+// SetDeprecation sets the date a deprecated resource was or will be deprecated. The Renderer
+// writes it as the RFC 9745 Deprecation header, e.g. "@1688169599", for DEPRECATED warnings.
+func (vm *ResponseWarningVM) SetDeprecation(date time.Time) *ResponseWarningVM {
+	vm.deprecation = date
+	return vm
+}
+
+// warningHeader formats the warning as a Warning header value with the
+// miscellaneous persistent warning code 299.
+func (vm *ResponseWarningVM) warningHeader() string {
+	text := vm.Message
+	if vm.Code != "" {
+		text = vm.Code + ": " + text
+	}
+
+	text = strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(text)
+	return `299 - "` + text + `"`
+}
+
+// AddWarnings appends non-fatal warnings to the response.
+// Nil warnings are skipped. This method uses method chaining pattern for fluent API design.
+func (vm *ResponseVM[T]) AddWarnings(warnings ...*ResponseWarningVM) *ResponseVM[T] {
+	for _, warning := range warnings {
+		if warning == nil {
+			continue
+		}
+
+		if vm.Warnings == nil {
+			vm.Warnings = &[]*ResponseWarningVM{}
+		}
+		*vm.Warnings = append(*vm.Warnings, warning)
+	}
+
+	return vm
+}
+
+// warningContextKey is the context key under which the warningCollector is stored.
+type warningContextKey struct{}
+
+// warningCollector accumulates warnings raised while handling a request.
+type warningCollector struct {
+	mu       sync.Mutex
+	warnings []*ResponseWarningVM
+}
+
+// WithWarnings returns a context that accumulates warnings added via AddWarning.
+// The Renderer appends accumulated warnings to every response rendered for a request
+// carrying the context. CollectWarnings installs it for whole handlers.
+func WithWarnings(ctx context.Context) context.Context {
+	if warningCollectorFromContext(ctx) != nil {
+		return ctx
+	}
+
+	return context.WithValue(ctx, warningContextKey{}, &warningCollector{})
+}
+
+// CollectWarnings is a middleware that lets handlers and the code they call
+// raise warnings via AddWarning.
+func CollectWarnings(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		next.ServeHTTP(w, r.WithContext(WithWarnings(r.Context())))
+	})
+}
+
+// AddWarning records a warning for the current request.
+// It reports false when the context does not accumulate warnings.
+func AddWarning(ctx context.Context, warning *ResponseWarningVM) bool {
+	collector := warningCollectorFromContext(ctx)
+	if collector == nil || warning == nil {
+		return false
+	}
+
+	collector.mu.Lock()
+	defer collector.mu.Unlock()
+
+	collector.warnings = append(collector.warnings, warning)
+	return true
+}
+
+// WarningsFromContext returns the warnings accumulated so far.
+func WarningsFromContext(ctx context.Context) []*ResponseWarningVM {
+	collector := warningCollectorFromContext(ctx)
+	if collector == nil {
+		return nil
+	}
+
+	collector.mu.Lock()
+	defer collector.mu.Unlock()
+
+	return append([]*ResponseWarningVM(nil), collector.warnings...)
+}
+
+// warningCollectorFromContext returns the collector installed by WithWarnings.
+func warningCollectorFromContext(ctx context.Context) *warningCollector {
+	collector, _ := ctx.Value(warningContextKey{}).(*warningCollector)
+	return collector
+}
+
+// setWarningHeaders mirrors warnings in the Warning and Deprecation headers.
+// The Deprecation header is an RFC 9745 date, so it is only sent for warnings that carry one.
+func setWarningHeaders(header http.Header, warnings []*ResponseWarningVM) {
+	for _, warning := range warnings {
+		header.Add("Warning", warning.warningHeader())
+
+		if warning.Code == WarningCodeDeprecated && !warning.deprecation.IsZero() && header.Get("Deprecation") == "" {
+			header.Set("Deprecation", "@"+strconv.FormatInt(warning.deprecation.Unix(), 10))
+		}
+	}
+}
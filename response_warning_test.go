@@ -0,0 +1,98 @@
+package gores
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestResponseVM_AddWarnings(t *testing.T) {
+	vm := NewResponseVM[*someStruct]().
+		SetCode(http.StatusOK).
+		SetData(&someStruct{SomeField: "value"}).
+		AddWarnings(
+			NewResponseWarningVM(WarningCodeDeprecated, "use /v2/users instead"),
+			nil,
+			NewResponseWarningVM("PARTIAL_DATA", "avatar service unavailable").SetField("avatar_url"),
+		)
+
+	if len(vm.warnings()) != 2 {
+		t.Fatalf("expected length of warnings is 2, got %d", len(vm.warnings()))
+	}
+
+	if (*vm.Warnings)[1].Field != "avatar_url" {
+		t.Errorf("expected warning field is %s, got %s", "avatar_url", (*vm.Warnings)[1].Field)
+	}
+}
+
+func TestRenderer_Warnings(t *testing.T) {
+	// The same envelope is rendered for every request and must not accumulate collected warnings
+	vm := NewResponseVM[*someStruct]().
+		SetCode(http.StatusOK).
+		SetData(&someStruct{SomeField: "value"}).
+		AddWarnings(NewResponseWarningVM("PARTIAL_DATA", "avatar service unavailable"))
+
+	handler := CollectWarnings(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !AddWarning(r.Context(), NewResponseWarningVM(WarningCodeDeprecated, `the "sort" parameter is deprecated`).SetField("sort")) {
+			t.Error("expected warning to be collected")
+		}
+
+		NewRenderer().Render(w, r, vm)
+	}))
+
+	for i := 0; i < 2; i++ {
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/", nil))
+
+		expected := `{"code":200,"data":{"SomeField":"value"},"warnings":[{"code":"PARTIAL_DATA","message":"avatar service unavailable"},{"code":"DEPRECATED","message":"the \"sort\" parameter is deprecated","field":"sort"}]}`
+		if w.Body.String() != expected {
+			t.Errorf("expected body is %s, got %s", expected, w.Body.String())
+		}
+
+		warnings := w.Header().Values("Warning")
+		if len(warnings) != 2 || warnings[1] != `299 - "DEPRECATED: the \"sort\" parameter is deprecated"` {
+			t.Errorf("unexpected warning headers %q", warnings)
+		}
+
+		if w.Header().Get("Deprecation") != "" {
+			t.Errorf("expected no deprecation header without a date, got %q", w.Header().Get("Deprecation"))
+		}
+	}
+
+	if len(vm.warnings()) != 1 {
+		t.Errorf("expected length of warnings is 1, got %d", len(vm.warnings()))
+	}
+}
+
+func TestRenderer_Warnings_Deprecation(t *testing.T) {
+	date := time.Date(2023, time.June, 30, 23, 59, 59, 0, time.UTC)
+
+	w := httptest.NewRecorder()
+	NewRenderer().Render(w, httptest.NewRequest(http.MethodGet, "/", nil), NewResponseVM[*someStruct]().
+		SetCode(http.StatusOK).
+		AddWarnings(
+			NewResponseWarningVM("PARTIAL_DATA", "avatar service unavailable"),
+			NewResponseWarningVM(WarningCodeDeprecated, "use /v2/users instead").SetDeprecation(date),
+		))
+
+	if w.Header().Get("Deprecation") != "@1688169599" {
+		t.Errorf("expected deprecation header is %s, got %q", "@1688169599", w.Header().Get("Deprecation"))
+	}
+}
+
+func TestAddWarning_WithoutCollector(t *testing.T) {
+	if AddWarning(context.Background(), NewResponseWarningVM("CODE", "message")) {
+		t.Error("expected warning not to be collected without a collector")
+	}
+
+	if WarningsFromContext(context.Background()) != nil {
+		t.Error("expected no warnings without a collector")
+	}
+
+	ctx := WithWarnings(context.Background())
+	if WithWarnings(ctx) != ctx {
+		t.Error("expected WithWarnings to reuse an existing collector")
+	}
+}
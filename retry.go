@@ -0,0 +1,105 @@
+package gores
+
+import (
+	"errors"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// transientError is a registered error that should be reported as retryable.
+type transientError struct {
+	target     error
+	retryAfter time.Duration
+}
+
+// transientErrors holds errors registered via RegisterTransientError.
+var transientErrors struct {
+	mu      sync.RWMutex
+	entries []transientError
+}
+
+// RegisterTransientError registers target as a transient failure.
+// Any error matching target via errors.Is is marked retryable by SetErrorFromError,
+// with retryAfter as the suggested delay when it is positive.
+// Registration is typically done once during program initialization.
+func RegisterTransientError(target error, retryAfter time.Duration) {
+	transientErrors.mu.Lock()
+	defer transientErrors.mu.Unlock()
+
+	transientErrors.entries = append(transientErrors.entries, transientError{
+		target:     target,
+		retryAfter: retryAfter,
+	})
+}
+
+// retryAfterError annotates an error with a retry delay.
+type retryAfterError struct {
+	err        error
+	retryAfter time.Duration
+}
+
+// WithRetryAfter wraps err so that SetErrorFromError marks it retryable with the given delay.
+// The wrapped error keeps its message and remains visible to errors.Is, errors.As and gocerr.
+func WithRetryAfter(err error, retryAfter time.Duration) error {
+	if err == nil {
+		return nil
+	}
+
+	return &retryAfterError{err: err, retryAfter: retryAfter}
+}
+
+// Error returns the message of the wrapped error.
+func (e *retryAfterError) Error() string {
+	return e.err.Error()
+}
+
+// Unwrap returns the wrapped error.
+func (e *retryAfterError) Unwrap() error {
+	return e.err
+}
+
+// isRetryableCode reports whether a status code signals a transient condition.
+func isRetryableCode(code int) bool {
+	switch code {
+	case http.StatusTooManyRequests, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	}
+
+	return false
+}
+
+// transientRetryAfter reports whether err is transient and its suggested retry delay.
+// An explicit WithRetryAfter annotation takes precedence over registered errors.
+func transientRetryAfter(err error) (time.Duration, bool) {
+	var annotated *retryAfterError
+	if errors.As(err, &annotated) {
+		return annotated.retryAfter, true
+	}
+
+	transientErrors.mu.RLock()
+	defer transientErrors.mu.RUnlock()
+
+	for _, entry := range transientErrors.entries {
+		if errors.Is(err, entry.target) {
+			return entry.retryAfter, true
+		}
+	}
+
+	return 0, false
+}
+
+// applyRetryability populates the retry metadata from the status code and error.
+func (vm *ResponseErrorVM) applyRetryability(code int, err error) *ResponseErrorVM {
+	retryAfter, transient := transientRetryAfter(err)
+
+	if transient || isRetryableCode(code) {
+		vm.Retryable = true
+	}
+
+	if retryAfter > 0 {
+		vm.SetRetryAfter(retryAfter)
+	}
+
+	return vm
+}
@@ -0,0 +1,83 @@
+package gores
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/fikri240794/gocerr"
+)
+
+func TestResponseVM_SetErrorFromError_Retryability(t *testing.T) {
+	errTransient := errors.New("upstream unavailable")
+	RegisterTransientError(errTransient, 3*time.Second)
+
+	testCases := []struct {
+		Name               string
+		Err                error
+		ExpectedRetryable  bool
+		ExpectedRetryAfter int
+	}{
+		{Name: "StandardError", Err: errors.New("boom")},
+		{Name: "BadRequest", Err: gocerr.New(http.StatusBadRequest, "bad request")},
+		{Name: "TooManyRequests", Err: gocerr.New(http.StatusTooManyRequests, "slow down"), ExpectedRetryable: true},
+		{Name: "ServiceUnavailable", Err: gocerr.New(http.StatusServiceUnavailable, "maintenance"), ExpectedRetryable: true},
+		{Name: "GatewayTimeout", Err: gocerr.New(http.StatusGatewayTimeout, "timeout"), ExpectedRetryable: true},
+		{Name: "RegisteredTransient", Err: fmt.Errorf("fetching: %w", errTransient), ExpectedRetryable: true, ExpectedRetryAfter: 3},
+		{Name: "WithRetryAfter", Err: WithRetryAfter(gocerr.New(http.StatusConflict, "locked"), 1500*time.Millisecond), ExpectedRetryable: true, ExpectedRetryAfter: 2},
+	}
+
+	for i := range testCases {
+		t.Run(testCases[i].Name, func(t *testing.T) {
+			vm := NewResponseVM[*someStruct]().SetErrorFromError(testCases[i].Err)
+
+			if vm.Error.Retryable != testCases[i].ExpectedRetryable {
+				t.Errorf("expected retryable is %t, got %t", testCases[i].ExpectedRetryable, vm.Error.Retryable)
+			}
+
+			if vm.Error.RetryAfter != testCases[i].ExpectedRetryAfter {
+				t.Errorf("expected retry after is %d, got %d", testCases[i].ExpectedRetryAfter, vm.Error.RetryAfter)
+			}
+		})
+	}
+}
+
+func TestWithRetryAfter(t *testing.T) {
+	if WithRetryAfter(nil, time.Second) != nil {
+		t.Error("expected nil error to stay nil")
+	}
+
+	err := WithRetryAfter(gocerr.New(http.StatusConflict, "locked"), time.Second)
+
+	if err.Error() != "locked" {
+		t.Errorf("expected message is %s, got %s", "locked", err.Error())
+	}
+
+	if code := NewResponseVM[*someStruct]().SetErrorFromError(err).Code; code != http.StatusConflict {
+		t.Errorf("expected code is %d, got %d", http.StatusConflict, code)
+	}
+}
+
+// TestRenderer_RetryAfterHeader tests that the error retry delay is mirrored in the Retry-After header
+func TestRenderer_RetryAfterHeader(t *testing.T) {
+	vm := NewResponseVM[*someStruct]().
+		SetCode(http.StatusServiceUnavailable).
+		SetError(NewResponseErrorVM().SetMessage("maintenance").SetRetryAfter(30 * time.Second))
+
+	w := httptest.NewRecorder()
+	if err := NewRenderer().Render(w, nil, vm); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if w.Header().Get("Retry-After") != "30" {
+		t.Errorf("expected retry after is %s, got %s", "30", w.Header().Get("Retry-After"))
+	}
+
+	expected := `{"code":503,"error":{"message":"maintenance","retryable":true,"retry_after":30}}`
+	if w.Body.String() != expected {
+		t.Errorf("expected body is %s, got %s", expected, w.Body.String())
+	}
+}
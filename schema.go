@@ -0,0 +1,522 @@
+package gores
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// KeyCase selects the naming convention applied to envelope keys.
+type KeyCase int
+
+const (
+	SnakeCase KeyCase = iota // error_fields (default)
+	CamelCase                // errorFields
+)
+
+// envelopeKeys are the top-level keys that never belong to the error object.
+// They are needed to tell error members apart when the error is flattened.
+var envelopeKeys = map[string]bool{
+	"code":     true,
+	"data":     true,
+	"errors":   true,
+	"meta":     true,
+	"warnings": true,
+}
+
+// EnvelopeSchema customizes the JSON shape of rendered envelopes without changing
+// the Go types. It renames keys, switches their case, drops the code from the body and
+// controls where error details are placed. Data payloads and nested error details such
+// as conflict are written untouched. A nil *EnvelopeSchema means the default shape.
+type EnvelopeSchema struct {
+	keys               map[string]string // Canonical key to custom key overrides
+	reverseKeys        map[string]string // Custom key to canonical key
+	keyCase            KeyCase           // Case applied to keys without an override
+	omitCode           bool              // Whether code is left out of the body
+	flattenError       bool              // Whether error members are lifted to the top level
+	flattenErrorFields bool              // Whether error_fields is lifted to the top level
+	fieldPathFormat    FieldPathFormat   // How field paths in error_fields are written
+	errorFieldsTree    bool              // Whether error_fields is written as a nested tree
+}
+
+// NewEnvelopeSchema creates a new schema that produces the default envelope shape.
+func NewEnvelopeSchema() *EnvelopeSchema {
+	return &EnvelopeSchema{
+		keys:        make(map[string]string),
+		reverseKeys: make(map[string]string),
+	}
+}
+
+// SetKey renames a canonical key such as "code", "data", "error", "message" or "error_fields".
+// Overrides are used verbatim and are not affected by the key case. Encode fails when a
+// member would be written under a name that belongs to another key, e.g. the errors of a
+// partial response after SetKey("error_fields", "errors"); rename "errors" too in that case.
+func (s *EnvelopeSchema) SetKey(canonical, name string) *EnvelopeSchema {
+	if previous, exists := s.keys[canonical]; exists {
+		delete(s.reverseKeys, previous)
+	}
+
+	s.keys[canonical] = name
+	s.reverseKeys[name] = canonical
+	return s
+}
+
+// SetKeyCase sets the naming convention for keys without an explicit override.
+// This method uses method chaining pattern for fluent API design.
+func (s *EnvelopeSchema) SetKeyCase(keyCase KeyCase) *EnvelopeSchema {
+	s.keyCase = keyCase
+	return s
+}
+
+// SetOmitCode sets whether the status code is left out of the body.
+// The status code is still sent in the HTTP status line.
+func (s *EnvelopeSchema) SetOmitCode(omitCode bool) *EnvelopeSchema {
+	s.omitCode = omitCode
+	return s
+}
+
+// SetFlattenError sets whether the members of the error object are written at the
+// top level of the envelope instead of under the error key.
+func (s *EnvelopeSchema) SetFlattenError(flattenError bool) *EnvelopeSchema {
+	s.flattenError = flattenError
+	return s
+}
+
+// SetFlattenErrorFields sets whether error_fields is written at the top level of the
+// envelope instead of nested under the error key.
+func (s *EnvelopeSchema) SetFlattenErrorFields(flattenErrorFields bool) *EnvelopeSchema {
+	s.flattenErrorFields = flattenErrorFields
+	return s
+}
+
+// SetFieldPathFormat sets how the field of every field error is written, e.g. FieldPathJSONPointer
+// turns "items[0].price" into "/items/0/price". The default FieldPathRaw leaves fields as set.
+func (s *EnvelopeSchema) SetFieldPathFormat(format FieldPathFormat) *EnvelopeSchema {
+	s.fieldPathFormat = format
+	return s
+}
+
+// SetErrorFieldsTree sets whether error_fields is written as nested objects instead of a list,
+// for form libraries that expect them; see ResponseErrorVM.ErrorFieldsTree. The tree only keeps
+// messages, so locations, codes and rejected values are dropped.
+func (s *EnvelopeSchema) SetErrorFieldsTree(errorFieldsTree bool) *EnvelopeSchema {
+	s.errorFieldsTree = errorFieldsTree
+	return s
+}
+
+// key returns the wire name of a canonical key.
+func (s *EnvelopeSchema) key(canonical string) string {
+	if name, exists := s.keys[canonical]; exists {
+		return name
+	}
+
+	if s.keyCase == CamelCase {
+		return snakeToCamel(canonical)
+	}
+
+	return canonical
+}
+
+// canonical returns the canonical name of a wire key.
+func (s *EnvelopeSchema) canonical(name string) string {
+	if canonical, exists := s.reverseKeys[name]; exists {
+		return canonical
+	}
+
+	if s.keyCase == CamelCase {
+		return camelToSnake(name)
+	}
+
+	return name
+}
+
+// Encode rewrites a default-shaped envelope body into the schema's shape.
+func (s *EnvelopeSchema) Encode(body []byte) ([]byte, error) {
+	if s == nil {
+		return body, nil
+	}
+
+	members, err := decodeJSONObject(body)
+	if err != nil {
+		return nil, err
+	}
+
+	out := make(jsonObject, 0, len(members))
+	for _, member := range members {
+		// A member written under a name another key was renamed to would be read back as that key
+		if name := s.key(member.key); envelopeKeys[member.key] && s.canonical(name) != member.key {
+			return nil, fmt.Errorf("gores: envelope schema writes %q as %q, which is the name of %q", member.key, name, s.canonical(name))
+		}
+
+		switch member.key {
+		case "code":
+			if s.omitCode {
+				continue
+			}
+			out = append(out, jsonMember{key: s.key("code"), value: member.value})
+		case "error":
+			errorMembers, fields, err := s.encodeError(member.value)
+			if err != nil {
+				return nil, err
+			}
+
+			if s.flattenError {
+				out = append(out, errorMembers...)
+			} else {
+				out = append(out, jsonMember{key: s.key("error"), value: errorMembers.raw()})
+			}
+
+			if fields != nil {
+				out = append(out, jsonMember{key: s.key("error_fields"), value: fields})
+			}
+		case "errors":
+			nested, err := s.mapArrayObjects(member.value, s.encodeNestedError)
+			if err != nil {
+				return nil, err
+			}
+			out = append(out, jsonMember{key: s.key("errors"), value: nested})
+		default:
+			out = append(out, jsonMember{key: s.key(member.key), value: member.value})
+		}
+	}
+
+	// Renamed keys must not shadow one another, or clients could not tell them apart
+	names := make(map[string]bool, len(out))
+	for _, member := range out {
+		if names[member.key] {
+			return nil, fmt.Errorf("gores: envelope schema writes more than one member as %q", member.key)
+		}
+		names[member.key] = true
+	}
+
+	return out.raw(), nil
+}
+
+// encodeError renames the members of an error object. When error fields are flattened
+// they are returned separately so the caller can place them at the top level.
+func (s *EnvelopeSchema) encodeError(raw json.RawMessage) (jsonObject, json.RawMessage, error) {
+	if isJSONNull(raw) {
+		return jsonObject{}, nil, nil
+	}
+
+	members, err := decodeJSONObject(raw)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var fields json.RawMessage
+	out := make(jsonObject, 0, len(members))
+
+	for _, member := range members {
+		value := member.value
+
+		if member.key == "error_fields" {
+			if value, err = s.encodeErrorFields(value); err != nil {
+				return nil, nil, err
+			}
+
+			if s.flattenErrorFields {
+				fields = value
+				continue
+			}
+		}
+
+		out = append(out, jsonMember{key: s.key(member.key), value: value})
+	}
+
+	return out, fields, nil
+}
+
+// encodeNestedError renames the members of an error object listed under errors.
+// Its error fields always stay nested.
+func (s *EnvelopeSchema) encodeNestedError(raw json.RawMessage) (json.RawMessage, error) {
+	members, err := decodeJSONObject(raw)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range members {
+		if members[i].key == "error_fields" {
+			if members[i].value, err = s.encodeErrorFields(members[i].value); err != nil {
+				return nil, err
+			}
+		}
+
+		members[i].key = s.key(members[i].key)
+	}
+
+	return members.raw(), nil
+}
+
+// encodeErrorFields applies the field path format, tree rendering and key names to error_fields.
+func (s *EnvelopeSchema) encodeErrorFields(raw json.RawMessage) (json.RawMessage, error) {
+	if isJSONNull(raw) || (s.fieldPathFormat == FieldPathRaw && !s.errorFieldsTree) {
+		return s.renameArrayObjects(raw, s.key)
+	}
+
+	errorVM := NewResponseErrorVM()
+	if err := json.Unmarshal(raw, &errorVM.ErrorFields); err != nil {
+		return nil, err
+	}
+
+	if s.errorFieldsTree {
+		return json.Marshal(errorVM.ErrorFieldsTree())
+	}
+
+	for _, errorField := range errorVM.ErrorFields {
+		if errorField != nil {
+			errorField.Field = ParseFieldPath(errorField.Field).Format(s.fieldPathFormat)
+		}
+	}
+
+	fields, err := json.Marshal(errorVM.ErrorFields)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.renameArrayObjects(fields, s.key)
+}
+
+// renameSchema returns a copy of an object schema whose members are renamed to their keys.
+func (s *EnvelopeSchema) renameSchema(object *Schema) *Schema {
+	renamed := *object
+	renamed.Properties = make(map[string]*Schema, len(object.Properties))
+	for name, property := range object.Properties {
+		renamed.Properties[s.key(name)] = property
+	}
+
+	renamed.Required = nil
+	for _, name := range object.Required {
+		renamed.Required = append(renamed.Required, s.key(name))
+	}
+
+	return &renamed
+}
+
+// Decode rewrites a schema-shaped envelope body back into the default shape,
+// so that it can be unmarshaled into ResponseVM.
+func (s *EnvelopeSchema) Decode(body []byte) ([]byte, error) {
+	if s == nil {
+		return body, nil
+	}
+
+	members, err := decodeJSONObject(body)
+	if err != nil {
+		return nil, err
+	}
+
+	var errorMembers jsonObject
+	out := make(jsonObject, 0, len(members))
+
+	for _, member := range members {
+		canonical := s.canonical(member.key)
+
+		switch {
+		case canonical == "error":
+			nested, err := decodeJSONObject(member.value)
+			if err != nil && !isJSONNull(member.value) {
+				return nil, err
+			}
+			for _, errorMember := range nested {
+				errorMembers = append(errorMembers, jsonMember{key: s.canonical(errorMember.key), value: errorMember.value})
+			}
+		case canonical == "error_fields" || (s.flattenError && !envelopeKeys[canonical]):
+			errorMembers = append(errorMembers, jsonMember{key: canonical, value: member.value})
+		case canonical == "errors":
+			nested, err := s.mapArrayObjects(member.value, s.decodeNestedError)
+			if err != nil {
+				return nil, err
+			}
+			out = append(out, jsonMember{key: canonical, value: nested})
+		default:
+			out = append(out, jsonMember{key: canonical, value: member.value})
+		}
+	}
+
+	if len(errorMembers) > 0 {
+		for i := range errorMembers {
+			if errorMembers[i].key != "error_fields" {
+				continue
+			}
+
+			if errorMembers[i].value, err = s.decodeErrorFields(errorMembers[i].value); err != nil {
+				return nil, err
+			}
+		}
+
+		out = append(out, jsonMember{key: "error", value: errorMembers.raw()})
+	}
+
+	return out.raw(), nil
+}
+
+// decodeNestedError restores the canonical members of an error object listed under errors.
+func (s *EnvelopeSchema) decodeNestedError(raw json.RawMessage) (json.RawMessage, error) {
+	members, err := decodeJSONObject(raw)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range members {
+		members[i].key = s.canonical(members[i].key)
+
+		if members[i].key == "error_fields" {
+			if members[i].value, err = s.decodeErrorFields(members[i].value); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	return members.raw(), nil
+}
+
+// decodeErrorFields restores the list shape and canonical keys of error_fields.
+// Trees are flattened with their paths written in the schema's field path format.
+func (s *EnvelopeSchema) decodeErrorFields(raw json.RawMessage) (json.RawMessage, error) {
+	if !s.errorFieldsTree || isJSONNull(raw) {
+		return s.renameArrayObjects(raw, s.canonical)
+	}
+
+	var tree map[string]interface{}
+	if err := json.Unmarshal(raw, &tree); err != nil {
+		return nil, err
+	}
+
+	return json.Marshal(errorFieldsFromTree(tree, s.fieldPathFormat))
+}
+
+// renameArrayObjects renames the keys of every object in a JSON array.
+func (s *EnvelopeSchema) renameArrayObjects(raw json.RawMessage, rename func(string) string) (json.RawMessage, error) {
+	return s.mapArrayObjects(raw, func(item json.RawMessage) (json.RawMessage, error) {
+		members, err := decodeJSONObject(item)
+		if err != nil {
+			return nil, err
+		}
+
+		for i := range members {
+			members[i].key = rename(members[i].key)
+		}
+
+		return members.raw(), nil
+	})
+}
+
+// mapArrayObjects rewrites every item of a JSON array with fn.
+func (s *EnvelopeSchema) mapArrayObjects(raw json.RawMessage, fn func(json.RawMessage) (json.RawMessage, error)) (json.RawMessage, error) {
+	if isJSONNull(raw) {
+		return raw, nil
+	}
+
+	var items []json.RawMessage
+	if err := json.Unmarshal(raw, &items); err != nil {
+		return nil, err
+	}
+
+	mapped := make([]json.RawMessage, 0, len(items))
+	for _, item := range items {
+		value, err := fn(item)
+		if err != nil {
+			return nil, err
+		}
+
+		mapped = append(mapped, value)
+	}
+
+	return json.Marshal(mapped)
+}
+
+// jsonMember is a single key/value pair of a JSON object.
+type jsonMember struct {
+	key   string
+	value json.RawMessage
+}
+
+// jsonObject is a JSON object that preserves member order.
+type jsonObject []jsonMember
+
+// raw encodes the object, keeping member order.
+func (o jsonObject) raw() json.RawMessage {
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+
+	for i, member := range o {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+
+		key, _ := json.Marshal(member.key)
+		buf.Write(key)
+		buf.WriteByte(':')
+		buf.Write(member.value)
+	}
+
+	buf.WriteByte('}')
+	return buf.Bytes()
+}
+
+// decodeJSONObject decodes a JSON object into its members in document order.
+func decodeJSONObject(raw []byte) (jsonObject, error) {
+	decoder := json.NewDecoder(bytes.NewReader(raw))
+
+	token, err := decoder.Token()
+	if err != nil {
+		return nil, err
+	}
+
+	if delim, ok := token.(json.Delim); !ok || delim != '{' {
+		return nil, errors.New("gores: envelope is not a JSON object")
+	}
+
+	var members jsonObject
+	for decoder.More() {
+		token, err := decoder.Token()
+		if err != nil {
+			return nil, err
+		}
+
+		var value json.RawMessage
+		if err := decoder.Decode(&value); err != nil {
+			return nil, err
+		}
+
+		members = append(members, jsonMember{key: token.(string), value: value})
+	}
+
+	return members, nil
+}
+
+// isJSONNull reports whether raw is empty or the JSON null literal.
+func isJSONNull(raw json.RawMessage) bool {
+	trimmed := bytes.TrimSpace(raw)
+	return len(trimmed) == 0 || bytes.Equal(trimmed, []byte("null"))
+}
+
+// snakeToCamel converts snake_case to camelCase.
+func snakeToCamel(s string) string {
+	parts := strings.Split(s, "_")
+	for i := 1; i < len(parts); i++ {
+		if parts[i] != "" {
+			parts[i] = strings.ToUpper(parts[i][:1]) + parts[i][1:]
+		}
+	}
+
+	return strings.Join(parts, "")
+}
+
+// camelToSnake converts camelCase to snake_case.
+func camelToSnake(s string) string {
+	var b strings.Builder
+	for i, c := range s {
+		if c >= 'A' && c <= 'Z' {
+			if i > 0 {
+				b.WriteByte('_')
+			}
+			c += 'a' - 'A'
+		}
+		b.WriteRune(c)
+	}
+
+	return b.String()
+}
@@ -0,0 +1,233 @@
+package gores
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/fikri240794/gocerr"
+)
+
+func TestEnvelopeSchema_Encode(t *testing.T) {
+	validation := NewResponseVM[*someStruct]().
+		SetErrorFromError(gocerr.New(
+			http.StatusUnprocessableEntity,
+			"validation failed",
+			gocerr.NewErrorField("email", "email is required"),
+		))
+	success := NewResponseVM[*someStruct]().
+		SetCode(http.StatusOK).
+		SetData(&someStruct{SomeField: "value"})
+
+	testCases := []struct {
+		Name     string
+		Schema   *EnvelopeSchema
+		Response *ResponseVM[*someStruct]
+		Expected string
+	}{
+		{
+			Name:     "NilSchema",
+			Response: validation,
+			Expected: `{"code":422,"error":{"message":"validation failed","error_fields":[{"field":"email","message":"email is required"}]}}`,
+		},
+		{
+			Name: "Legacy",
+			Schema: NewEnvelopeSchema().
+				SetKey("code", "status").
+				SetKey("data", "result").
+				SetKey("error_fields", "errors").
+				SetFlattenError(true),
+			Response: validation,
+			Expected: `{"status":422,"message":"validation failed","errors":[{"field":"email","message":"email is required"}]}`,
+		},
+		{
+			Name:     "LegacySuccess",
+			Schema:   NewEnvelopeSchema().SetKey("code", "status").SetKey("data", "result"),
+			Response: success,
+			Expected: `{"status":200,"result":{"SomeField":"value"}}`,
+		},
+		{
+			Name:     "CamelCaseWithoutCode",
+			Schema:   NewEnvelopeSchema().SetKeyCase(CamelCase).SetOmitCode(true),
+			Response: validation,
+			Expected: `{"error":{"message":"validation failed","errorFields":[{"field":"email","message":"email is required"}]}}`,
+		},
+		{
+			Name:     "FlattenErrorFields",
+			Schema:   NewEnvelopeSchema().SetFlattenErrorFields(true),
+			Response: validation,
+			Expected: `{"code":422,"error":{"message":"validation failed"},"error_fields":[{"field":"email","message":"email is required"}]}`,
+		},
+	}
+
+	for i := range testCases {
+		t.Run(testCases[i].Name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			if err := NewRenderer().SetSchema(testCases[i].Schema).Render(w, nil, testCases[i].Response); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if w.Body.String() != testCases[i].Expected {
+				t.Errorf("expected body is %s, got %s", testCases[i].Expected, w.Body.String())
+			}
+
+			// The client parser must recover the original envelope
+			resp := w.Result()
+			actual, err := DecodeResponseWithSchema[*someStruct](resp, testCases[i].Schema)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			testResponseVMEquality(t, testCases[i].Response, actual)
+		})
+	}
+}
+
+func TestEnvelopeSchema_Decode_InvalidBody(t *testing.T) {
+	if _, err := NewEnvelopeSchema().Decode([]byte(`[1,2]`)); err == nil {
+		t.Error("expected error for non-object body")
+	}
+
+	var target map[string]interface{}
+	body, err := (*EnvelopeSchema)(nil).Decode([]byte(`{"code":200}`))
+	if err != nil || json.Unmarshal(body, &target) != nil {
+		t.Errorf("expected nil schema to pass body through, got %v", err)
+	}
+}
+
+func TestKeyCaseConversion(t *testing.T) {
+	testCases := []struct {
+		Snake string
+		Camel string
+	}{
+		{Snake: "code", Camel: "code"},
+		{Snake: "error_fields", Camel: "errorFields"},
+		{Snake: "retry_after", Camel: "retryAfter"},
+		{Snake: "current_version", Camel: "currentVersion"},
+	}
+
+	for i := range testCases {
+		if actual := snakeToCamel(testCases[i].Snake); actual != testCases[i].Camel {
+			t.Errorf("expected camel case is %s, got %s", testCases[i].Camel, actual)
+		}
+
+		if actual := camelToSnake(testCases[i].Camel); actual != testCases[i].Snake {
+			t.Errorf("expected snake case is %s, got %s", testCases[i].Snake, actual)
+		}
+	}
+}
+
+func TestEnvelopeSchema_ErrorFieldPaths(t *testing.T) {
+	response := NewResponseVM[*someStruct]().
+		SetErrorFromError(gocerr.New(
+			http.StatusUnprocessableEntity,
+			"validation failed",
+			gocerr.NewErrorField("items[0].price", "must be positive"),
+			gocerr.NewErrorField("email", "is invalid"),
+		))
+
+	body, err := json.Marshal(response)
+	if err != nil {
+		t.Fatalf("expected error is nil, got %v", err)
+	}
+
+	testCases := []struct {
+		Name     string
+		Schema   *EnvelopeSchema
+		Expected string
+	}{
+		{
+			Name:     "JSONPointer",
+			Schema:   NewEnvelopeSchema().SetFieldPathFormat(FieldPathJSONPointer),
+			Expected: `{"code":422,"error":{"message":"validation failed","error_fields":[{"field":"/items/0/price","message":"must be positive"},{"field":"/email","message":"is invalid"}]}}`,
+		},
+		{
+			Name:     "Tree",
+			Schema:   NewEnvelopeSchema().SetErrorFieldsTree(true).SetKeyCase(CamelCase),
+			Expected: `{"code":422,"error":{"message":"validation failed","errorFields":{"email":["is invalid"],"items":{"0":{"price":["must be positive"]}}}}}`,
+		},
+	}
+
+	for i := range testCases {
+		t.Run(testCases[i].Name, func(t *testing.T) {
+			encoded, err := testCases[i].Schema.Encode(body)
+			if err != nil {
+				t.Fatalf("expected error is nil, got %v", err)
+			}
+
+			if string(encoded) != testCases[i].Expected {
+				t.Errorf("expected body is %s, got %s", testCases[i].Expected, string(encoded))
+			}
+
+			decoded, err := testCases[i].Schema.Decode(encoded)
+			if err != nil {
+				t.Fatalf("expected error is nil, got %v", err)
+			}
+
+			var actual ResponseVM[*someStruct]
+			if err := json.Unmarshal(decoded, &actual); err != nil {
+				t.Fatalf("expected error is nil, got %v", err)
+			}
+
+			if len(actual.Error.ErrorFields) != 2 {
+				t.Errorf("expected length of error fields is 2, got %d", len(actual.Error.ErrorFields))
+			}
+		})
+	}
+}
+
+func TestEnvelopeSchema_Encode_KeyCollision(t *testing.T) {
+	partial := NewResponseVM[*someStruct]().
+		SetCode(http.StatusOK).
+		SetData(&someStruct{SomeField: "value"}).
+		AddErrors(NewResponseErrorVM().SetMessage("orders unavailable").SetPath("orders"))
+
+	testCases := []struct {
+		Name          string
+		Schema        *EnvelopeSchema
+		Expected      string
+		ExpectedError bool
+	}{
+		{
+			Name:          "ErrorFieldsRenamedToErrors",
+			Schema:        NewEnvelopeSchema().SetKey("error_fields", "errors").SetFlattenError(true),
+			ExpectedError: true,
+		},
+		{
+			Name:     "ErrorsRenamedToo",
+			Schema:   NewEnvelopeSchema().SetKey("error_fields", "errors").SetKey("errors", "failures").SetFlattenError(true),
+			Expected: `{"code":200,"failures":[{"message":"orders unavailable","path":"orders"}],"data":{"SomeField":"value"}}`,
+		},
+		{
+			Name:          "TwoKeysSameName",
+			Schema:        NewEnvelopeSchema().SetKey("data", "result").SetKey("code", "result"),
+			ExpectedError: true,
+		},
+	}
+
+	for i := range testCases {
+		t.Run(testCases[i].Name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			err := NewRenderer().SetSchema(testCases[i].Schema).Render(w, nil, partial)
+			if (err != nil) != testCases[i].ExpectedError {
+				t.Fatalf("expected error is %t, got %v", testCases[i].ExpectedError, err)
+			}
+
+			if testCases[i].ExpectedError {
+				return
+			}
+
+			if w.Body.String() != testCases[i].Expected {
+				t.Errorf("expected body is %s, got %s", testCases[i].Expected, w.Body.String())
+			}
+
+			actual, err := DecodeResponseWithSchema[*someStruct](w.Result(), testCases[i].Schema)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			testResponseVMEquality(t, partial, actual)
+		})
+	}
+}
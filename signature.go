@@ -0,0 +1,242 @@
+package gores
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// DefaultSignatureHeader is the response header carrying the detached JWS signature.
+const DefaultSignatureHeader = "X-JWS-Signature"
+
+// JWS algorithm names of the supported signature schemes.
+const (
+	SigningHS256 = "HS256" // HMAC with SHA-256
+	SigningEdDSA = "EdDSA" // Ed25519
+)
+
+var (
+	// ErrSignatureMissing is returned when a response that must be signed carries no signature.
+	ErrSignatureMissing = errors.New("gores: response signature missing")
+
+	// ErrSignatureInvalid is returned when a response signature is malformed or does not
+	// match the body, i.e. the envelope was tampered with or signed with another key.
+	ErrSignatureInvalid = errors.New("gores: response signature invalid")
+)
+
+// jwsHeader is the protected header of a signature.
+type jwsHeader struct {
+	Algorithm string `json:"alg"`
+	KeyID     string `json:"kid,omitempty"`
+}
+
+// ResponseSigner signs response bodies with a detached JWS in compact serialization
+// (RFC 7515 Appendix F), i.e. "header..signature" where the omitted payload is the
+// canonical body. Any JWS library can verify it by re-inserting the base64url body.
+type ResponseSigner struct {
+	algorithm string
+	keyID     string
+	header    string
+	sign      func(input []byte) []byte
+}
+
+// NewHMACSigner creates a signer producing HS256 signatures with the shared key.
+func NewHMACSigner(key []byte) *ResponseSigner {
+	return &ResponseSigner{
+		algorithm: SigningHS256,
+		header:    DefaultSignatureHeader,
+		sign: func(input []byte) []byte {
+			mac := hmac.New(sha256.New, key)
+			mac.Write(input)
+			return mac.Sum(nil)
+		},
+	}
+}
+
+// NewEd25519Signer creates a signer producing EdDSA signatures with the private key.
+func NewEd25519Signer(key ed25519.PrivateKey) *ResponseSigner {
+	return &ResponseSigner{
+		algorithm: SigningEdDSA,
+		header:    DefaultSignatureHeader,
+		sign: func(input []byte) []byte {
+			return ed25519.Sign(key, input)
+		},
+	}
+}
+
+// SetKeyID sets the "kid" header parameter so verifiers can select the key during rotation.
+// This method uses method chaining pattern for fluent API design.
+func (s *ResponseSigner) SetKeyID(keyID string) *ResponseSigner {
+	s.keyID = keyID
+	return s
+}
+
+// SetHeader sets the response header carrying the signature.
+// This method uses method chaining pattern for fluent API design.
+func (s *ResponseSigner) SetHeader(header string) *ResponseSigner {
+	s.header = header
+	return s
+}
+
+// Sign returns the detached JWS of body. The body should already be canonical JSON,
+// which the Renderer guarantees for every body it signs.
+func (s *ResponseSigner) Sign(body []byte) (string, error) {
+	header, err := json.Marshal(jwsHeader{Algorithm: s.algorithm, KeyID: s.keyID})
+	if err != nil {
+		return "", err
+	}
+
+	protected := base64.RawURLEncoding.EncodeToString(header)
+	signature := s.sign(signingInput(protected, body))
+
+	return protected + ".." + base64.RawURLEncoding.EncodeToString(signature), nil
+}
+
+// signingInput returns the JWS signing input of a detached payload.
+func signingInput(protected string, body []byte) []byte {
+	return []byte(protected + "." + base64.RawURLEncoding.EncodeToString(body))
+}
+
+// SignatureVerifier checks detached JWS signatures produced by a ResponseSigner.
+// Bodies are canonicalized before verification, so re-serialization by intermediaries
+// that preserves the JSON value does not break the signature, while any change does.
+type SignatureVerifier struct {
+	algorithm string
+	keyID     string
+	header    string
+	verify    func(input, signature []byte) bool
+}
+
+// NewHMACVerifier creates a verifier of HS256 signatures made with the shared key.
+func NewHMACVerifier(key []byte) *SignatureVerifier {
+	return &SignatureVerifier{
+		algorithm: SigningHS256,
+		header:    DefaultSignatureHeader,
+		verify: func(input, signature []byte) bool {
+			mac := hmac.New(sha256.New, key)
+			mac.Write(input)
+			return hmac.Equal(mac.Sum(nil), signature)
+		},
+	}
+}
+
+// NewEd25519Verifier creates a verifier of EdDSA signatures made with the key pair of key.
+func NewEd25519Verifier(key ed25519.PublicKey) *SignatureVerifier {
+	return &SignatureVerifier{
+		algorithm: SigningEdDSA,
+		header:    DefaultSignatureHeader,
+		verify: func(input, signature []byte) bool {
+			return ed25519.Verify(key, input, signature)
+		},
+	}
+}
+
+// SetKeyID requires signatures to name keyID in their "kid" header parameter.
+// This method uses method chaining pattern for fluent API design.
+func (v *SignatureVerifier) SetKeyID(keyID string) *SignatureVerifier {
+	v.keyID = keyID
+	return v
+}
+
+// SetHeader sets the response header carrying the signature.
+// This method uses method chaining pattern for fluent API design.
+func (v *SignatureVerifier) SetHeader(header string) *SignatureVerifier {
+	v.header = header
+	return v
+}
+
+// Verify checks the detached JWS signature of body. It returns an error wrapping
+// ErrSignatureInvalid when the signature is malformed, uses another algorithm or key ID,
+// or does not match the canonical form of body.
+func (v *SignatureVerifier) Verify(signature string, body []byte) error {
+	protected, encoded, ok := splitDetachedJWS(signature)
+	if !ok {
+		return fmt.Errorf("%w: not a detached JWS", ErrSignatureInvalid)
+	}
+
+	rawHeader, err := base64.RawURLEncoding.DecodeString(protected)
+	if err != nil {
+		return fmt.Errorf("%w: malformed header", ErrSignatureInvalid)
+	}
+
+	var header jwsHeader
+	if err := json.Unmarshal(rawHeader, &header); err != nil {
+		return fmt.Errorf("%w: malformed header", ErrSignatureInvalid)
+	}
+
+	// The algorithm is pinned by the verifier, never taken from the signature
+	if header.Algorithm != v.algorithm {
+		return fmt.Errorf("%w: unexpected algorithm %q", ErrSignatureInvalid, header.Algorithm)
+	}
+
+	if v.keyID != "" && header.KeyID != v.keyID {
+		return fmt.Errorf("%w: unexpected key ID %q", ErrSignatureInvalid, header.KeyID)
+	}
+
+	rawSignature, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return fmt.Errorf("%w: malformed signature", ErrSignatureInvalid)
+	}
+
+	canonical, err := CanonicalJSON(body)
+	if err != nil {
+		return fmt.Errorf("%w: body is not valid JSON: %v", ErrSignatureInvalid, err)
+	}
+
+	if !v.verify(signingInput(protected, canonical), rawSignature) {
+		return fmt.Errorf("%w: signature does not match the body", ErrSignatureInvalid)
+	}
+
+	return nil
+}
+
+// VerifyResponse checks the signature header of resp against its body. The body is
+// buffered and restored so it can still be decoded. Responses that carry no body by
+// definition, i.e. 204, 304 and responses to HEAD, are not checked.
+func (v *SignatureVerifier) VerifyResponse(resp *http.Response) error {
+	if !hasResponseBody(resp) {
+		return nil
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return err
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+
+	signature := resp.Header.Get(v.header)
+	if signature == "" {
+		return ErrSignatureMissing
+	}
+
+	return v.Verify(signature, body)
+}
+
+// splitDetachedJWS splits a compact JWS with an empty payload into its protected header
+// and signature.
+func splitDetachedJWS(jws string) (string, string, bool) {
+	parts := strings.Split(jws, ".")
+	if len(parts) != 3 || parts[1] != "" {
+		return "", "", false
+	}
+
+	return parts[0], parts[2], true
+}
+
+// hasResponseBody reports whether resp is expected to carry a body.
+func hasResponseBody(resp *http.Response) bool {
+	if resp.StatusCode == http.StatusNoContent || resp.StatusCode == http.StatusNotModified {
+		return false
+	}
+
+	return resp.Request == nil || resp.Request.Method != http.MethodHead
+}
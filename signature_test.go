@@ -0,0 +1,237 @@
+package gores
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"crypto/rand"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestSignatureVerifier_Verify(t *testing.T) {
+	publicKey, privateKey, _ := ed25519.GenerateKey(rand.Reader)
+	otherPublicKey, _, _ := ed25519.GenerateKey(rand.Reader)
+	body := []byte(`{"code":200,"data":{"id":1}}`)
+
+	sign := func(signer *ResponseSigner, body []byte) string {
+		signature, err := signer.Sign(body)
+		if err != nil {
+			t.Fatalf("unexpected error %v", err)
+		}
+		return signature
+	}
+
+	testCases := []struct {
+		Name          string
+		Signature     string
+		Verifier      *SignatureVerifier
+		Body          []byte
+		ExpectedError error
+	}{
+		{
+			Name:      "HMAC",
+			Signature: sign(NewHMACSigner([]byte("secret")), body),
+			Verifier:  NewHMACVerifier([]byte("secret")),
+			Body:      body,
+		},
+		{
+			Name:      "Ed25519",
+			Signature: sign(NewEd25519Signer(privateKey).SetKeyID("2024-01"), body),
+			Verifier:  NewEd25519Verifier(publicKey).SetKeyID("2024-01"),
+			Body:      body,
+		},
+		{
+			Name:      "ReformattedBody",
+			Signature: sign(NewHMACSigner([]byte("secret")), body),
+			Verifier:  NewHMACVerifier([]byte("secret")),
+			Body:      []byte("{\n  \"data\": {\"id\": 1.0},\n  \"code\": 200\n}"),
+		},
+		{
+			Name:          "TamperedBody",
+			Signature:     sign(NewHMACSigner([]byte("secret")), body),
+			Verifier:      NewHMACVerifier([]byte("secret")),
+			Body:          []byte(`{"code":200,"data":{"id":2}}`),
+			ExpectedError: ErrSignatureInvalid,
+		},
+		{
+			Name:          "WrongKey",
+			Signature:     sign(NewEd25519Signer(privateKey), body),
+			Verifier:      NewEd25519Verifier(otherPublicKey),
+			Body:          body,
+			ExpectedError: ErrSignatureInvalid,
+		},
+		{
+			Name:          "WrongAlgorithm",
+			Signature:     sign(NewHMACSigner([]byte("secret")), body),
+			Verifier:      NewEd25519Verifier(publicKey),
+			Body:          body,
+			ExpectedError: ErrSignatureInvalid,
+		},
+		{
+			Name:          "WrongKeyID",
+			Signature:     sign(NewHMACSigner([]byte("secret")).SetKeyID("old"), body),
+			Verifier:      NewHMACVerifier([]byte("secret")).SetKeyID("new"),
+			Body:          body,
+			ExpectedError: ErrSignatureInvalid,
+		},
+		{
+			Name:          "AttachedPayload",
+			Signature:     strings.Replace(sign(NewHMACSigner([]byte("secret")), body), "..", ".e30.", 1),
+			Verifier:      NewHMACVerifier([]byte("secret")),
+			Body:          body,
+			ExpectedError: ErrSignatureInvalid,
+		},
+		{
+			Name:          "Malformed",
+			Signature:     "not-a-signature",
+			Verifier:      NewHMACVerifier([]byte("secret")),
+			Body:          body,
+			ExpectedError: ErrSignatureInvalid,
+		},
+	}
+
+	for i := range testCases {
+		t.Run(testCases[i].Name, func(t *testing.T) {
+			err := testCases[i].Verifier.Verify(testCases[i].Signature, testCases[i].Body)
+			if !errors.Is(err, testCases[i].ExpectedError) {
+				t.Errorf("expected error is %v, got %v", testCases[i].ExpectedError, err)
+			}
+		})
+	}
+}
+
+func TestRenderer_SetSigner(t *testing.T) {
+	publicKey, privateKey, _ := ed25519.GenerateKey(rand.Reader)
+	renderer := NewRenderer().SetSigner(NewEd25519Signer(privateKey).SetHeader("Signature-JWS"))
+
+	recorder := httptest.NewRecorder()
+	renderer.Render(recorder, httptest.NewRequest(http.MethodGet, "/", nil), NewResponseVM[*someStruct]().
+		SetCode(http.StatusOK).
+		SetData(&someStruct{SomeField: "value"}))
+
+	expectedBody := `{"code":200,"data":{"SomeField":"value"}}`
+	if recorder.Body.String() != expectedBody {
+		t.Errorf("expected body is %s, got %s", expectedBody, recorder.Body.String())
+	}
+
+	verifier := NewEd25519Verifier(publicKey).SetHeader("Signature-JWS")
+	if err := verifier.Verify(recorder.Header().Get("Signature-JWS"), recorder.Body.Bytes()); err != nil {
+		t.Errorf("unexpected error %v", err)
+	}
+
+	resp := recorder.Result()
+	resp.Request = httptest.NewRequest(http.MethodGet, "/", nil)
+	if err := verifier.VerifyResponse(resp); err != nil {
+		t.Errorf("unexpected error %v", err)
+	}
+
+	// The body is restored for decoding
+	vm, err := DecodeResponse[*someStruct](resp)
+	if err != nil || vm.Data.SomeField != "value" {
+		t.Errorf("expected decoded data, got %+v, %v", vm, err)
+	}
+}
+
+func TestRenderer_SetCanonical(t *testing.T) {
+	recorder := httptest.NewRecorder()
+	NewRenderer().
+		SetCanonical(true).
+		SetSchema(NewEnvelopeSchema().SetKey("data", "result")).
+		Render(recorder, nil, NewResponseVM[*someStruct]().SetCode(http.StatusCreated).SetData(&someStruct{SomeField: "<b>"}))
+
+	expectedBody := `{"code":201,"result":{"SomeField":"<b>"}}`
+	if recorder.Body.String() != expectedBody {
+		t.Errorf("expected body is %s, got %s", expectedBody, recorder.Body.String())
+	}
+}
+
+func TestRenderer_LargeIntegers(t *testing.T) {
+	type identified struct {
+		ID int64 `json:"id"`
+	}
+
+	publicKey, privateKey, _ := ed25519.GenerateKey(rand.Reader)
+
+	testCases := []struct {
+		Name     string
+		Renderer *Renderer
+		ID       int64
+	}{
+		{Name: "Canonical", Renderer: NewRenderer().SetCanonical(true), ID: 9007199254740993},
+		{Name: "Signed", Renderer: NewRenderer().SetSigner(NewEd25519Signer(privateKey)), ID: 1234567890123456789},
+	}
+
+	for i := range testCases {
+		t.Run(testCases[i].Name, func(t *testing.T) {
+			recorder := httptest.NewRecorder()
+			err := testCases[i].Renderer.Render(recorder, nil, NewResponseVM[*identified]().
+				SetCode(http.StatusOK).
+				SetData(&identified{ID: testCases[i].ID}))
+			if err != nil {
+				t.Fatalf("unexpected error %v", err)
+			}
+
+			expectedBody := `{"code":200,"data":{"id":` + strconv.FormatInt(testCases[i].ID, 10) + `}}`
+			if recorder.Body.String() != expectedBody {
+				t.Errorf("expected body is %s, got %s", expectedBody, recorder.Body.String())
+			}
+
+			if testCases[i].Renderer.signer == nil {
+				return
+			}
+
+			if err := NewEd25519Verifier(publicKey).Verify(recorder.Header().Get(DefaultSignatureHeader), recorder.Body.Bytes()); err != nil {
+				t.Errorf("unexpected error %v", err)
+			}
+		})
+	}
+}
+
+func TestClient_SetVerifier(t *testing.T) {
+	key := []byte("secret")
+	tamper := false
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		recorder := httptest.NewRecorder()
+		NewRenderer().SetSigner(NewHMACSigner(key)).Render(recorder, r, NewResponseVM[*someStruct]().
+			SetCode(http.StatusOK).
+			SetData(&someStruct{SomeField: "value"}))
+
+		body := recorder.Body.Bytes()
+		if tamper {
+			body = bytes.Replace(body, []byte("value"), []byte("forged"), 1)
+		}
+
+		w.Header().Set(DefaultSignatureHeader, recorder.Header().Get(DefaultSignatureHeader))
+		w.Write(body)
+	}))
+	defer server.Close()
+
+	client := NewClient(server.Client()).SetVerifier(NewHMACVerifier(key))
+
+	req, _ := http.NewRequest(http.MethodGet, server.URL, nil)
+	resp, err := client.Do(req)
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	body, _ := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if !strings.Contains(string(body), "value") {
+		t.Errorf("expected body to be readable after verification, got %s", body)
+	}
+
+	tamper = true
+	req, _ = http.NewRequest(http.MethodGet, server.URL, nil)
+	if _, err := client.Do(req); !errors.Is(err, ErrSignatureInvalid) {
+		t.Errorf("expected error is %v, got %v", ErrSignatureInvalid, err)
+	}
+
+	req, _ = http.NewRequest(http.MethodGet, server.URL, nil)
+	if _, err := NewClient(server.Client()).SetVerifier(NewHMACVerifier(key).SetHeader("X-Other")).Do(req); !errors.Is(err, ErrSignatureMissing) {
+		t.Errorf("expected error is %v, got %v", ErrSignatureMissing, err)
+	}
+}
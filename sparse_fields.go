@@ -0,0 +1,223 @@
+package gores
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// Codes of responses rejected because the sparse fieldset names fields the data does not have.
+const (
+	ErrorCodeUnknownFields = "UNKNOWN_FIELDS" // Error code of the response
+	FieldCodeUnknownField  = "UNKNOWN_FIELD"  // Code of the field error of each unknown field
+)
+
+// fieldSelection is a parsed sparse fieldset such as "id,name,owner.email".
+// A nil subtree selects the whole member.
+type fieldSelection map[string]fieldSelection
+
+// parseFieldSelection parses a comma-separated list of dotted field paths.
+// Selecting a member as a whole wins over selecting some of its fields.
+func parseFieldSelection(value string) fieldSelection {
+	selection := fieldSelection{}
+	for _, field := range strings.Split(value, ",") {
+		field = strings.TrimSpace(field)
+		if field == "" {
+			continue
+		}
+
+		node := selection
+		keys := strings.Split(field, ".")
+		for i, key := range keys {
+			child, exists := node[key]
+			if exists && child == nil {
+				break
+			}
+
+			if i == len(keys)-1 {
+				node[key] = nil
+				break
+			}
+
+			if !exists {
+				child = fieldSelection{}
+				node[key] = child
+			}
+			node = child
+		}
+	}
+
+	return selection
+}
+
+// unknown returns the selected field paths that values of type t cannot have, sorted.
+// Maps, interfaces and custom JSON encodings accept any field; values encoded as
+// strings, such as times, accept none.
+func (fs fieldSelection) unknown(t reflect.Type, prefix string) []string {
+	if t == nil || len(fs) == 0 {
+		return nil
+	}
+	t = indirectType(t)
+
+	var unknown []string
+	switch {
+	case t == timeType || reflect.PtrTo(t).Implements(textMarshalerType):
+	case t.Kind() == reflect.Interface || reflect.PtrTo(t).Implements(jsonMarshalerType):
+		return nil
+	case t.Kind() == reflect.Slice || t.Kind() == reflect.Array:
+		if t.Elem().Kind() != reflect.Uint8 {
+			return fs.unknown(t.Elem(), prefix)
+		}
+	case t.Kind() == reflect.Map:
+		for key, child := range fs {
+			if child != nil {
+				unknown = append(unknown, child.unknown(t.Elem(), prefix+key+".")...)
+			}
+		}
+		sort.Strings(unknown)
+		return unknown
+	case t.Kind() == reflect.Struct:
+		fields := jsonFieldTypes(t)
+		for key, child := range fs {
+			fieldType, exists := fields[key]
+			if !exists {
+				unknown = append(unknown, prefix+key)
+			} else if child != nil {
+				unknown = append(unknown, child.unknown(fieldType, prefix+key+".")...)
+			}
+		}
+		sort.Strings(unknown)
+		return unknown
+	}
+
+	// Values without members cannot have any of the selected fields
+	for key := range fs {
+		unknown = append(unknown, prefix+key)
+	}
+	sort.Strings(unknown)
+	return unknown
+}
+
+// jsonFieldTypes returns the types of the members encoding/json writes for the struct type t,
+// keyed by member name and inlining embedded structs.
+func jsonFieldTypes(t reflect.Type) map[string]reflect.Type {
+	fields := make(map[string]reflect.Type)
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		tag := field.Tag.Get("json")
+		if tag == "-" || (!field.IsExported() && !field.Anonymous) {
+			continue
+		}
+
+		name, _, _ := strings.Cut(tag, ",")
+		if field.Anonymous && name == "" && indirectType(field.Type).Kind() == reflect.Struct {
+			for embeddedName, embeddedType := range jsonFieldTypes(indirectType(field.Type)) {
+				if _, exists := fields[embeddedName]; !exists {
+					fields[embeddedName] = embeddedType
+				}
+			}
+			continue
+		}
+
+		if !field.IsExported() {
+			continue
+		}
+
+		if name == "" {
+			name = field.Name
+		}
+		fields[name] = field.Type
+	}
+
+	return fields
+}
+
+// filter keeps only the selected members of every object in raw, recursing into arrays.
+// Scalars and null are returned unchanged.
+func (fs fieldSelection) filter(raw json.RawMessage) (json.RawMessage, error) {
+	trimmed := bytes.TrimSpace(raw)
+	if len(trimmed) == 0 {
+		return raw, nil
+	}
+
+	switch trimmed[0] {
+	case '{':
+		members, err := decodeJSONObject(trimmed)
+		if err != nil {
+			return nil, err
+		}
+
+		kept := make(jsonObject, 0, len(fs))
+		for _, member := range members {
+			child, selected := fs[member.key]
+			if !selected {
+				continue
+			}
+
+			if child != nil {
+				if member.value, err = child.filter(member.value); err != nil {
+					return nil, err
+				}
+			}
+			kept = append(kept, member)
+		}
+
+		return kept.raw(), nil
+	case '[':
+		var items []json.RawMessage
+		if err := json.Unmarshal(trimmed, &items); err != nil {
+			return nil, err
+		}
+
+		for i := range items {
+			filtered, err := fs.filter(items[i])
+			if err != nil {
+				return nil, err
+			}
+			items[i] = filtered
+		}
+
+		return json.Marshal(items)
+	}
+
+	return raw, nil
+}
+
+// fieldSelection returns the sparse fieldset requested with r, or nil when none is.
+// An empty or blank parameter, such as ?fields=, selects nothing and is ignored.
+func (rd *Renderer) fieldSelection(r *http.Request) fieldSelection {
+	if rd.fieldsParam == "" || r == nil {
+		return nil
+	}
+
+	values, requested := r.URL.Query()[rd.fieldsParam]
+	if !requested {
+		return nil
+	}
+
+	fields := parseFieldSelection(strings.Join(values, ","))
+	if len(fields) == 0 {
+		return nil
+	}
+
+	return fields
+}
+
+// unknownFieldsError describes a sparse fieldset naming unknown fields.
+func unknownFieldsError(param string, unknown []string) *ResponseErrorVM {
+	errVM := NewResponseErrorVM().
+		SetMessage("unknown fields requested: " + strings.Join(unknown, ", ")).
+		SetErrorCode(ErrorCodeUnknownFields)
+
+	for _, field := range unknown {
+		errVM.AddErrorFields(NewResponseErrorFieldVM(param, "unknown field "+field).
+			SetLocation(LocationQuery).
+			SetCode(FieldCodeUnknownField).
+			SetRejectedValue(field))
+	}
+
+	return errVM
+}
@@ -0,0 +1,241 @@
+package gores
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/fikri240794/gocerr"
+)
+
+type sparseOwner struct {
+	Email string `json:"email"`
+	Name  string `json:"name"`
+}
+
+type sparseAudit struct {
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type sparseProject struct {
+	sparseAudit
+	ID      int                    `json:"id"`
+	Name    string                 `json:"name"`
+	Owner   *sparseOwner           `json:"owner"`
+	Members []sparseOwner          `json:"members,omitempty"`
+	Labels  map[string]string      `json:"labels,omitempty"`
+	Extra   map[string]sparseOwner `json:"extra,omitempty"`
+	Secret  string                 `json:"-"`
+}
+
+func TestParseFieldSelection(t *testing.T) {
+	testCases := []struct {
+		Name     string
+		Value    string
+		Expected fieldSelection
+	}{
+		{Name: "Empty", Value: "", Expected: fieldSelection{}},
+		{Name: "Flat", Value: "id, name,,", Expected: fieldSelection{"id": nil, "name": nil}},
+		{Name: "Nested", Value: "owner.email,owner.name", Expected: fieldSelection{"owner": {"email": nil, "name": nil}}},
+		{Name: "WholeMemberWins", Value: "owner.email,owner", Expected: fieldSelection{"owner": nil}},
+		{Name: "WholeMemberFirst", Value: "owner,owner.email", Expected: fieldSelection{"owner": nil}},
+	}
+
+	for i := range testCases {
+		t.Run(testCases[i].Name, func(t *testing.T) {
+			if actual := parseFieldSelection(testCases[i].Value); !reflect.DeepEqual(actual, testCases[i].Expected) {
+				t.Errorf("expected selection is %v, got %v", testCases[i].Expected, actual)
+			}
+		})
+	}
+}
+
+func TestFieldSelection_unknown(t *testing.T) {
+	testCases := []struct {
+		Name     string
+		Type     reflect.Type
+		Fields   string
+		Expected []string
+	}{
+		{Name: "Known", Type: reflect.TypeOf(&sparseProject{}), Fields: "id,name,owner.email,members.name,created_at"},
+		{Name: "Unknown", Type: reflect.TypeOf(&sparseProject{}), Fields: "id,title,owner.mail,Secret", Expected: []string{"Secret", "owner.mail", "title"}},
+		{Name: "Slice", Type: reflect.TypeOf([]*sparseProject{}), Fields: "id,nope", Expected: []string{"nope"}},
+		{Name: "MapKeys", Type: reflect.TypeOf(&sparseProject{}), Fields: "labels.anything,extra.key.email,extra.key.phone", Expected: []string{"extra.key.phone"}},
+		{Name: "Scalar", Type: reflect.TypeOf(&sparseProject{}), Fields: "name.first,created_at.year", Expected: []string{"created_at.year", "name.first"}},
+		{Name: "Interface", Type: reflect.TypeOf(map[string]interface{}{}), Fields: "a.b.c"},
+		{Name: "RawMessage", Type: reflect.TypeOf(json.RawMessage{}), Fields: "a"},
+		{Name: "NilType", Type: nil, Fields: "a"},
+	}
+
+	for i := range testCases {
+		t.Run(testCases[i].Name, func(t *testing.T) {
+			actual := parseFieldSelection(testCases[i].Fields).unknown(testCases[i].Type, "")
+			if !reflect.DeepEqual(actual, testCases[i].Expected) {
+				t.Errorf("expected unknown fields are %v, got %v", testCases[i].Expected, actual)
+			}
+		})
+	}
+}
+
+func TestFieldSelection_filter(t *testing.T) {
+	testCases := []struct {
+		Name     string
+		Fields   string
+		Input    string
+		Expected string
+	}{
+		{Name: "Object", Fields: "name,id", Input: `{"id":1,"name":"a","owner":null}`, Expected: `{"id":1,"name":"a"}`},
+		{Name: "Nested", Fields: "owner.email", Input: `{"id":1,"owner":{"email":"e","name":"n"}}`, Expected: `{"owner":{"email":"e"}}`},
+		{Name: "NullNested", Fields: "owner.email", Input: `{"id":1,"owner":null}`, Expected: `{"owner":null}`},
+		{Name: "Array", Fields: "id", Input: `[{"id":1,"name":"a"},{"id":2,"name":"b"}]`, Expected: `[{"id":1},{"id":2}]`},
+		{Name: "NestedArray", Fields: "members.name", Input: `{"members":[{"email":"e","name":"n"}]}`, Expected: `{"members":[{"name":"n"}]}`},
+		{Name: "Scalar", Fields: "id", Input: `"text"`, Expected: `"text"`},
+		{Name: "NothingSelected", Fields: "", Input: `{"id":1}`, Expected: `{}`},
+	}
+
+	for i := range testCases {
+		t.Run(testCases[i].Name, func(t *testing.T) {
+			actual, err := parseFieldSelection(testCases[i].Fields).filter(json.RawMessage(testCases[i].Input))
+			if err != nil {
+				t.Fatalf("unexpected error %v", err)
+			}
+
+			if string(actual) != testCases[i].Expected {
+				t.Errorf("expected filtered JSON is %s, got %s", testCases[i].Expected, actual)
+			}
+		})
+	}
+}
+
+func TestRenderer_SetFieldsParam(t *testing.T) {
+	project := &sparseProject{ID: 1, Name: "gores", Owner: &sparseOwner{Email: "a@b.c", Name: "alice"}}
+	success := NewResponseVM[*sparseProject]().SetCode(http.StatusOK).SetData(project)
+
+	testCases := []struct {
+		Name         string
+		Renderer     *Renderer
+		URL          string
+		VM           Envelope
+		ExpectedCode int
+		ExpectedBody string
+	}{
+		{
+			Name:         "Disabled",
+			Renderer:     NewRenderer(),
+			URL:          "/?fields=id",
+			VM:           success,
+			ExpectedCode: http.StatusOK,
+			ExpectedBody: `{"code":200,"data":{"created_at":"0001-01-01T00:00:00Z","id":1,"name":"gores","owner":{"email":"a@b.c","name":"alice"}}}`,
+		},
+		{
+			Name:         "NotRequested",
+			Renderer:     NewRenderer().SetFieldsParam("fields"),
+			URL:          "/",
+			VM:           NewResponseVM[*sparseOwner]().SetCode(http.StatusOK).SetData(project.Owner),
+			ExpectedCode: http.StatusOK,
+			ExpectedBody: `{"code":200,"data":{"email":"a@b.c","name":"alice"}}`,
+		},
+		{
+			Name:         "Selected",
+			Renderer:     NewRenderer().SetFieldsParam("fields"),
+			URL:          "/?fields=id,owner.email",
+			VM:           success,
+			ExpectedCode: http.StatusOK,
+			ExpectedBody: `{"code":200,"data":{"id":1,"owner":{"email":"a@b.c"}}}`,
+		},
+		{
+			Name:         "RepeatedParam",
+			Renderer:     NewRenderer().SetFieldsParam("fields"),
+			URL:          "/?fields=id&fields=name",
+			VM:           success,
+			ExpectedCode: http.StatusOK,
+			ExpectedBody: `{"code":200,"data":{"id":1,"name":"gores"}}`,
+		},
+		{
+			Name:         "EmptyParam",
+			Renderer:     NewRenderer().SetFieldsParam("fields"),
+			URL:          "/?fields=",
+			VM:           success,
+			ExpectedCode: http.StatusOK,
+			ExpectedBody: `{"code":200,"data":{"created_at":"0001-01-01T00:00:00Z","id":1,"name":"gores","owner":{"email":"a@b.c","name":"alice"}}}`,
+		},
+		{
+			Name:         "BlankParam",
+			Renderer:     NewRenderer().SetFieldsParam("fields"),
+			URL:          "/?fields=%20,%20",
+			VM:           success,
+			ExpectedCode: http.StatusOK,
+			ExpectedBody: `{"code":200,"data":{"created_at":"0001-01-01T00:00:00Z","id":1,"name":"gores","owner":{"email":"a@b.c","name":"alice"}}}`,
+		},
+		{
+			Name:         "RepeatedParamWithEmptyValue",
+			Renderer:     NewRenderer().SetFieldsParam("fields"),
+			URL:          "/?fields=&fields=name",
+			VM:           success,
+			ExpectedCode: http.StatusOK,
+			ExpectedBody: `{"code":200,"data":{"name":"gores"}}`,
+		},
+		{
+			Name:         "List",
+			Renderer:     NewRenderer().SetFieldsParam("fields"),
+			URL:          "/?fields=name",
+			VM:           NewResponseVM[*[]sparseProject]().SetCode(http.StatusOK).SetData(&[]sparseProject{{ID: 1, Name: "a"}, {ID: 2, Name: "b"}}),
+			ExpectedCode: http.StatusOK,
+			ExpectedBody: `{"code":200,"data":[{"name":"a"},{"name":"b"}]}`,
+		},
+		{
+			Name:         "Schema",
+			Renderer:     NewRenderer().SetFieldsParam("select").SetSchema(NewEnvelopeSchema().SetKey("data", "result")),
+			URL:          "/?select=name",
+			VM:           success,
+			ExpectedCode: http.StatusOK,
+			ExpectedBody: `{"code":200,"result":{"name":"gores"}}`,
+		},
+		{
+			Name:         "Bare",
+			Renderer:     NewRenderer().SetFieldsParam("fields").SetBareMode(true),
+			URL:          "/?fields=name",
+			VM:           success,
+			ExpectedCode: http.StatusOK,
+			ExpectedBody: `{"name":"gores"}`,
+		},
+		{
+			Name:         "ErrorUntouched",
+			Renderer:     NewRenderer().SetFieldsParam("fields"),
+			URL:          "/?fields=unknown",
+			VM:           NewResponseVM[*sparseProject]().SetErrorFromError(gocerr.New(http.StatusNotFound, "not found")),
+			ExpectedCode: http.StatusNotFound,
+			ExpectedBody: `{"code":404,"error":{"message":"not found"}}`,
+		},
+		{
+			Name:         "UnknownFields",
+			Renderer:     NewRenderer().SetFieldsParam("fields"),
+			URL:          "/?fields=id,title,owner.mail",
+			VM:           success,
+			ExpectedCode: http.StatusBadRequest,
+			ExpectedBody: `{"code":400,"error":{"message":"unknown fields requested: owner.mail, title","error_code":"UNKNOWN_FIELDS","error_fields":[` +
+				`{"field":"fields","message":"unknown field owner.mail","location":"query","code":"UNKNOWN_FIELD","rejected_value":"owner.mail"},` +
+				`{"field":"fields","message":"unknown field title","location":"query","code":"UNKNOWN_FIELD","rejected_value":"title"}]}}`,
+		},
+	}
+
+	for i := range testCases {
+		t.Run(testCases[i].Name, func(t *testing.T) {
+			recorder := httptest.NewRecorder()
+			if err := testCases[i].Renderer.Render(recorder, httptest.NewRequest(http.MethodGet, testCases[i].URL, nil), testCases[i].VM); err != nil {
+				t.Fatalf("unexpected error %v", err)
+			}
+
+			if recorder.Code != testCases[i].ExpectedCode {
+				t.Errorf("expected status code is %d, got %d", testCases[i].ExpectedCode, recorder.Code)
+			}
+
+			if recorder.Body.String() != testCases[i].ExpectedBody {
+				t.Errorf("expected body is\n%s\ngot\n%s", testCases[i].ExpectedBody, recorder.Body.String())
+			}
+		})
+	}
+}
@@ -0,0 +1,149 @@
+package gores
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/fikri240794/gocerr"
+)
+
+// Timeout is a middleware that bounds handler execution time like http.TimeoutHandler,
+// but answers timed out requests with a gores error envelope instead of plain text.
+// Handler output is buffered until the handler returns, so streaming handlers
+// should not be wrapped.
+type Timeout struct {
+	timeout  time.Duration
+	code     int
+	message  string
+	renderer *Renderer
+}
+
+// NewTimeout creates a new Timeout middleware with the given limit.
+// Timed out requests receive HTTP 503 Service Unavailable by default, matching http.TimeoutHandler.
+func NewTimeout(timeout time.Duration) *Timeout {
+	return &Timeout{
+		timeout:  timeout,
+		code:     http.StatusServiceUnavailable,
+		message:  "request timed out",
+		renderer: NewRenderer(),
+	}
+}
+
+// SetCode sets the status code of timed out responses, typically 503 or 504.
+// This method uses method chaining pattern for fluent API design.
+func (m *Timeout) SetCode(code int) *Timeout {
+	m.code = code
+	return m
+}
+
+// SetMessage sets the error message of timed out responses.
+// This method uses method chaining pattern for fluent API design.
+func (m *Timeout) SetMessage(message string) *Timeout {
+	m.message = message
+	return m
+}
+
+// SetRenderer sets the Renderer used for timed out responses.
+// This method uses method chaining pattern for fluent API design.
+func (m *Timeout) SetRenderer(renderer *Renderer) *Timeout {
+	m.renderer = renderer
+	return m
+}
+
+// Handler wraps next with the timeout.
+// After the deadline, writes by the handler fail with http.ErrHandlerTimeout.
+// When the client disconnects first nothing is written at all.
+func (m *Timeout) Handler(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx, cancel := context.WithTimeout(r.Context(), m.timeout)
+		defer cancel()
+
+		tw := &timeoutWriter{header: make(http.Header)}
+		done := make(chan struct{})
+		panicked := make(chan interface{}, 1)
+
+		go func() {
+			defer func() {
+				if p := recover(); p != nil {
+					panicked <- p
+				}
+			}()
+
+			next.ServeHTTP(tw, r.WithContext(ctx))
+			close(done)
+		}()
+
+		select {
+		case p := <-panicked:
+			panic(p)
+		case <-done:
+			tw.mu.Lock()
+			defer tw.mu.Unlock()
+
+			header := w.Header()
+			for key, values := range tw.header {
+				header[key] = values
+			}
+
+			if tw.code == 0 {
+				tw.code = http.StatusOK
+			}
+
+			w.WriteHeader(tw.code)
+			w.Write(tw.body.Bytes())
+		case <-ctx.Done():
+			tw.mu.Lock()
+			defer tw.mu.Unlock()
+
+			tw.timedOut = true
+
+			// A canceled parent context means the client is gone and the Renderer writes nothing
+			m.renderer.RenderError(w, r, gocerr.New(m.code, m.message))
+		}
+	})
+}
+
+// timeoutWriter buffers a handler response until the handler returns or times out.
+type timeoutWriter struct {
+	mu       sync.Mutex
+	header   http.Header
+	body     bytes.Buffer
+	code     int
+	timedOut bool
+}
+
+// Header returns the buffered response headers.
+func (tw *timeoutWriter) Header() http.Header {
+	return tw.header
+}
+
+// Write buffers p, failing with http.ErrHandlerTimeout after the deadline.
+func (tw *timeoutWriter) Write(p []byte) (int, error) {
+	tw.mu.Lock()
+	defer tw.mu.Unlock()
+
+	if tw.timedOut {
+		return 0, http.ErrHandlerTimeout
+	}
+
+	if tw.code == 0 {
+		tw.code = http.StatusOK
+	}
+
+	return tw.body.Write(p)
+}
+
+// WriteHeader records the status code of the buffered response.
+func (tw *timeoutWriter) WriteHeader(code int) {
+	tw.mu.Lock()
+	defer tw.mu.Unlock()
+
+	if tw.timedOut || tw.code != 0 {
+		return
+	}
+
+	tw.code = code
+}
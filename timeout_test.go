@@ -0,0 +1,101 @@
+package gores
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestTimeout_Handler(t *testing.T) {
+	slow := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-r.Context().Done()
+	})
+	fast := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-Handled", "true")
+		w.WriteHeader(http.StatusCreated)
+		w.Write([]byte("done"))
+	})
+
+	testCases := []struct {
+		Name           string
+		Timeout        *Timeout
+		Handler        http.Handler
+		ExpectedCode   int
+		ExpectedBody   string
+		ExpectedHeader string
+	}{
+		{
+			Name:         "TimedOutDefault",
+			Timeout:      NewTimeout(10 * time.Millisecond),
+			Handler:      slow,
+			ExpectedCode: http.StatusServiceUnavailable,
+			ExpectedBody: `{"code":503,"error":{"message":"request timed out","retryable":true}}`,
+		},
+		{
+			Name:         "TimedOutGatewayTimeout",
+			Timeout:      NewTimeout(10 * time.Millisecond).SetCode(http.StatusGatewayTimeout).SetMessage("upstream too slow"),
+			Handler:      slow,
+			ExpectedCode: http.StatusGatewayTimeout,
+			ExpectedBody: `{"code":504,"error":{"message":"upstream too slow","retryable":true}}`,
+		},
+		{
+			Name:           "Completed",
+			Timeout:        NewTimeout(time.Second),
+			Handler:        fast,
+			ExpectedCode:   http.StatusCreated,
+			ExpectedBody:   "done",
+			ExpectedHeader: "true",
+		},
+	}
+
+	for i := range testCases {
+		t.Run(testCases[i].Name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			testCases[i].Timeout.Handler(testCases[i].Handler).ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/", nil))
+
+			if w.Code != testCases[i].ExpectedCode {
+				t.Errorf("expected code is %d, got %d", testCases[i].ExpectedCode, w.Code)
+			}
+
+			if w.Body.String() != testCases[i].ExpectedBody {
+				t.Errorf("expected body is %s, got %s", testCases[i].ExpectedBody, w.Body.String())
+			}
+
+			if w.Header().Get("X-Handled") != testCases[i].ExpectedHeader {
+				t.Errorf("expected header is %q, got %q", testCases[i].ExpectedHeader, w.Header().Get("X-Handled"))
+			}
+		})
+	}
+}
+
+// TestTimeout_Handler_ClientGone tests that nothing is written when the client disconnects first
+func TestTimeout_Handler_ClientGone(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	handler := NewTimeout(time.Second).Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		cancel()
+		<-r.Context().Done()
+	}))
+
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/", nil).WithContext(ctx))
+
+	if w.Body.Len() != 0 {
+		t.Errorf("expected empty body, got %s", w.Body.String())
+	}
+}
+
+// TestTimeoutWriter_TimedOut tests that writes after the deadline are rejected
+func TestTimeoutWriter_TimedOut(t *testing.T) {
+	tw := &timeoutWriter{header: make(http.Header), timedOut: true}
+
+	if _, err := tw.Write([]byte("late")); err != http.ErrHandlerTimeout {
+		t.Errorf("expected error is %v, got %v", http.ErrHandlerTimeout, err)
+	}
+
+	tw.WriteHeader(http.StatusOK)
+	if tw.code != 0 {
+		t.Errorf("expected code is 0, got %d", tw.code)
+	}
+}